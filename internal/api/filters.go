@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"groupie-tracker-search-bar/internal/catalog"
+)
+
+// maxMemberFilter is the largest member count offered as a checkbox on the artists page.
+const maxMemberFilter = 8
+
+// ParseArtistFilter reads the artists listing filters from query parameters:
+// created_from, created_to, album_from, album_to, members (repeated) and location.
+// Invalid values are ignored, the same way the pagination parameters are.
+func ParseArtistFilter(query url.Values) catalog.Filter {
+	var f catalog.Filter
+	f.Created.From = queryYear(query, "created_from")
+	f.Created.To = queryYear(query, "created_to")
+	f.Album.From = queryYear(query, "album_from")
+	f.Album.To = queryYear(query, "album_to")
+
+	seen := map[int]bool{}
+	for _, value := range query["members"] {
+		n, err := strconv.Atoi(value)
+		if err == nil && n >= 1 && n <= maxMemberFilter && !seen[n] {
+			seen[n] = true
+			f.Members = append(f.Members, n)
+		}
+	}
+	sort.Ints(f.Members)
+
+	f.Location = strings.TrimSpace(query.Get("location"))
+	return f
+}
+
+// queryYear returns the year in query parameter key, or 0 when it is missing or invalid
+func queryYear(query url.Values, key string) int {
+	year, err := strconv.Atoi(query.Get(key))
+	if err != nil || year < 1 {
+		return 0
+	}
+	return year
+}
+
+// artistFilterValues turns f back into query parameters, so the filters survive pagination
+func artistFilterValues(f catalog.Filter) url.Values {
+	values := url.Values{}
+	setYear := func(key string, year int) {
+		if year != 0 {
+			values.Set(key, strconv.Itoa(year))
+		}
+	}
+	setYear("created_from", f.Created.From)
+	setYear("created_to", f.Created.To)
+	setYear("album_from", f.Album.From)
+	setYear("album_to", f.Album.To)
+	for _, n := range f.Members {
+		values.Add("members", strconv.Itoa(n))
+	}
+	if f.Location != "" {
+		values.Set("location", f.Location)
+	}
+	return values
+}
+
+// artistSort is the sort order of the artists listing, read from the sort and order query parameters
+type artistSort struct {
+	Key        string // one of the catalog sort keys, or "" for upstream order
+	Descending bool
+}
+
+// parseArtistSort reads sort and order, falling back to upstream order for unknown keys
+func parseArtistSort(query url.Values) artistSort {
+	key := query.Get("sort")
+	if !catalog.IsSortKey(key) {
+		return artistSort{}
+	}
+	return artistSort{Key: key, Descending: query.Get("order") == "desc"}
+}
+
+// Order returns "asc" or "desc"
+func (s artistSort) Order() string {
+	if s.Descending {
+		return "desc"
+	}
+	return "asc"
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"groupie-tracker-search-bar/internal/geocode"
+)
+
+// geocoder locates concert places for the artist page maps. Until SetGeocoder
+// is called, only the built-in gazetteer is consulted.
+var geocoder geocode.Geocoder = geocode.Gazetteer()
+
+// mapboxToken is the access token the artist page maps load their tiles with
+var mapboxToken string
+
+// SetMapboxToken sets the access token handed to browsers for the artist page
+// maps. Without one the pages say the map is unavailable. It must be called
+// before the server starts handling requests.
+func SetMapboxToken(token string) {
+	mapboxToken = token
+}
+
+// SetGeocoder replaces the geocoder used for the artist page maps.
+// It must be called before the server starts handling requests.
+func SetGeocoder(g geocode.Geocoder) {
+	geocoder = g
+}
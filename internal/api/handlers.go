@@ -1,23 +1,56 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"text/template"
+	"time"
 
+	"groupie-tracker-search-bar/internal/catalog"
 	"groupie-tracker-search-bar/internal/fetch"
+	"groupie-tracker-search-bar/internal/geocode"
 	"groupie-tracker-search-bar/internal/models"
+	"groupie-tracker-search-bar/internal/search"
+	"groupie-tracker-search-bar/internal/validate"
+	pages "groupie-tracker-search-bar/templates"
 )
 
-var (
-	artists       []models.Artist
-	locationsData models.LocationsData
-	datesData     models.DatesData
-	relationsData models.RelationsData
-)
+// state is the data being served: a catalog and the search engine built from
+// it. It is never modified once stored, only replaced as a whole.
+type state struct {
+	catalog *catalog.Catalog
+	search  *search.Engine
+}
+
+// newState indexes c for searching
+func newState(c *catalog.Catalog) *state {
+	return &state{catalog: c, search: search.NewEngine(c, search.DefaultOptions())}
+}
+
+// current holds the state being served. Handlers take a single reference
+// per request, so a concurrent refresh never mixes old and new data.
+var current atomic.Pointer[state]
+
+var emptyState = newState(catalog.New(models.Dataset{}))
+
+// currentState returns the state to serve the current request from
+func currentState() *state {
+	if s := current.Load(); s != nil {
+		return s
+	}
+	return emptyState
+}
+
+// currentCatalog returns the catalog to serve the current request from
+func currentCatalog() *catalog.Catalog {
+	return currentState().catalog
+}
 
 func Subtract(a, b int) int {
 	return a - b
@@ -27,19 +60,30 @@ func Add(a, b int) int {
 	return a + b
 }
 
+// PlaceName returns the display name of an upstream location slug
+func PlaceName(slug string) string {
+	return models.ParsePlace(slug).String()
+}
+
+// Upcoming reports whether a concert has not happened yet
+func Upcoming(c models.Concert) bool {
+	return c.Upcoming(time.Now())
+}
+
 var templateFuncs = template.FuncMap{
 	"Join":     strings.Join, // Register the Join function
 	"subtract": Subtract,
 	"add":      Add,
+	"upcoming": Upcoming,
+	"place":    PlaceName,
 }
 
-var templates = template.Must(template.New("").Funcs(templateFuncs).ParseGlob("templates/*.html"))
+var templates = template.Must(template.New("").Funcs(templateFuncs).ParseFS(pages.FS, "*.html"))
 
-// InitData loads data when the application starts
-func InitData() error {
-	var err error
-	artists, locationsData, datesData, relationsData, err = fetch.FetchAllData()
-	return err
+// InitData loads data from source when the application starts,
+// handling integrity issues according to policy
+func InitData(ctx context.Context, source fetch.DataSource, policy validate.Policy) error {
+	return RefreshData(ctx, source, policy)
 }
 
 // RenderError displays a custom error page with status code and message
@@ -63,14 +107,24 @@ func IndexHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ArtistsHandler handles requests to the artists listing page with pagination
+// ArtistsHandler handles requests to the artists listing page with filters and pagination
 func ArtistsHandler(w http.ResponseWriter, r *http.Request) {
+	data := currentCatalog()
+
 	// Check if the data was successfully loaded
-	if len(artists) == 0 {
+	if data.Len() == 0 {
 		RenderError(w, http.StatusInternalServerError, "Failed to load artist data. Please check your internet connection.")
 		return
 	}
 
+	// Apply the filters before paginating so page counts reflect the filtered list
+	filter := ParseArtistFilter(r.URL.Query())
+	entries := data.Filter(filter)
+
+	// Sort before paginating so every page follows the same order
+	sortOrder := parseArtistSort(r.URL.Query())
+	catalog.SortEntries(entries, sortOrder.Key, sortOrder.Descending)
+
 	// Get 'page' and 'limit' query parameters
 	pageStr := r.URL.Query().Get("page")
 	limitStr := r.URL.Query().Get("limit")
@@ -85,43 +139,92 @@ func ArtistsHandler(w http.ResponseWriter, r *http.Request) {
 		limit = 20
 	}
 
+	// Calculate total pages, keeping at least one page for an empty result
+	totalPages := (len(entries) + limit - 1) / limit // Round up
+	if totalPages == 0 {
+		totalPages = 1
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+
 	// Calculate the start and end index for pagination
 	startIndex := (page - 1) * limit
 	endIndex := startIndex + limit
 
 	// Ensure the endIndex doesn't exceed the total number of artists
-	if endIndex > len(artists) {
-		endIndex = len(artists)
+	if endIndex > len(entries) {
+		endIndex = len(entries)
 	}
 
 	// Paginate the artists list
-	paginatedArtists := artists[startIndex:endIndex]
+	paginatedArtists := make([]models.Artist, 0, endIndex-startIndex)
+	for _, entry := range entries[startIndex:endIndex] {
+		paginatedArtists = append(paginatedArtists, entry.Artist)
+	}
 
-	// Calculate total pages
-	totalPages := (len(artists) + limit - 1) / limit // Round up
+	// Offer one checkbox per member count
+	type memberOption struct {
+		Count   int
+		Checked bool
+	}
+	var memberOptions []memberOption
+	for n := 1; n <= maxMemberFilter; n++ {
+		memberOptions = append(memberOptions, memberOption{Count: n, Checked: filter.HasMembers(n)})
+	}
 
 	// Pass paginated data and metadata to the template
-	data := struct {
-		Artists     []models.Artist
-		TotalPages  int
-		CurrentPage int
-		HasPrevPage bool
-		HasNextPage bool
+	pageData := struct {
+		Artists       []models.Artist
+		TotalArtists  int
+		TotalPages    int
+		CurrentPage   int
+		HasPrevPage   bool
+		HasNextPage   bool
+		PrevPageURL   string
+		NextPageURL   string
+		Filter        catalog.Filter
+		FilterActive  bool
+		Sort          artistSort
+		MemberOptions []memberOption
+		Places        []models.Place
 	}{
-		Artists:     paginatedArtists,
-		TotalPages:  totalPages,
-		CurrentPage: page,
-		HasPrevPage: page > 1,
-		HasNextPage: page < totalPages,
+		Artists:       paginatedArtists,
+		TotalArtists:  len(entries),
+		TotalPages:    totalPages,
+		CurrentPage:   page,
+		HasPrevPage:   page > 1,
+		HasNextPage:   page < totalPages,
+		PrevPageURL:   artistsPageURL(filter, sortOrder, page-1, limit),
+		NextPageURL:   artistsPageURL(filter, sortOrder, page+1, limit),
+		Filter:        filter,
+		FilterActive:  !filter.IsZero(),
+		Sort:          sortOrder,
+		MemberOptions: memberOptions,
+		Places:        data.Places(),
 	}
 
 	// Render the artists template with pagination
-	err = templates.ExecuteTemplate(w, "artists.html", data)
+	err = templates.ExecuteTemplate(w, "artists.html", pageData)
 	if err != nil {
 		RenderError(w, http.StatusInternalServerError, "Error loading the artists page")
 	}
 }
 
+// artistsPageURL links to a page of the artists listing, keeping the filters, the sort order and a non-default limit
+func artistsPageURL(filter catalog.Filter, sortOrder artistSort, page, limit int) string {
+	values := artistFilterValues(filter)
+	if sortOrder.Key != "" {
+		values.Set("sort", sortOrder.Key)
+		values.Set("order", sortOrder.Order())
+	}
+	values.Set("page", strconv.Itoa(page))
+	if limit != 20 {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+	return "/artists?" + values.Encode()
+}
+
 // ArtistDetailHandler handles requests to individual artist detail pages
 func ArtistDetailHandler(w http.ResponseWriter, r *http.Request) {
 	idStr := strings.TrimPrefix(r.URL.Path, "/artist/")
@@ -131,50 +234,36 @@ func ArtistDetailHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	index := id - 1
-	if index < 0 || index >= len(artists) {
+	// Fetch the artist details
+	artistDetail, ok := currentCatalog().Detail(id)
+	if !ok {
 		RenderError(w, http.StatusNotFound, "Artist not found")
 		return
 	}
 
-	// Fetch the artist details
-	artistDetail := models.ArtistDetail{
-		Artist:    artists[index],
-		Locations: locationsData.Index[index],
-		Dates:     datesData.Index[index],
-		Relations: relationsData.Index[index],
-	}
-
-	// Handle geocoding failures gracefully by stopping if there's no internet
+	// Locate the concerts for the map. Places the geocoder cannot locate are
+	// left off the map and marked in the list, rather than failing the page.
 	type ConcertLocation struct {
 		LocationName string    `json:"locationName"`
-		Coordinates  []float64 `json:"coordinates"`
-	}
-	var concertLocations []ConcertLocation
-	geocodeChan := make(chan ConcertLocation)
-	errorChan := make(chan error)
-
-	// Launch concurrent geocoding tasks
-	for _, location := range artistDetail.Locations.Locations {
-		go func(loc string) {
-			coords, err := fetch.GeocodeLocation(loc)
-			if err != nil {
-				errorChan <- err
-				return
-			}
-			geocodeChan <- ConcertLocation{LocationName: loc, Coordinates: coords}
-		}(location)
-	}
-
-	// Collect geocoded results or errors
-	for range artistDetail.Locations.Locations {
-		select {
-		case geocode := <-geocodeChan:
-			concertLocations = append(concertLocations, geocode)
-		case err := <-errorChan:
-			log.Printf("Error geocoding location: %v", err)
-			RenderError(w, http.StatusInternalServerError, "Error geocoding concert locations. Please check your internet connection.")
-			return
+		Coordinates  []float64 `json:"coordinates,omitempty"`
+		Unresolved   bool      `json:"unresolved,omitempty"`
+	}
+	places := make([]models.Place, len(artistDetail.Locations.Locations))
+	for i, location := range artistDetail.Locations.Locations {
+		places[i] = models.ParsePlace(location)
+	}
+	resolved, failed := geocode.ResolveAll(r.Context(), geocoder, places)
+
+	concertLocations := make([]ConcertLocation, len(places))
+	for i, place := range places {
+		concertLocations[i].LocationName = PlaceName(artistDetail.Locations.Locations[i])
+		if coords, ok := resolved[place]; ok {
+			concertLocations[i].Coordinates = coords.LngLat()
+			continue
+		}
+		concertLocations[i].Unresolved = true
+		if err := failed[place]; !errors.Is(err, geocode.ErrNotFound) {
+			log.Printf("Error geocoding %v: %v", place, err)
 		}
 	}
 
@@ -187,49 +276,24 @@ func ArtistDetailHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Pass the concert locations to the template
-	data := struct {
+	yearsToFirstAlbum, _ := artistDetail.Artist.YearsToFirstAlbum()
+	pageData := struct {
 		ArtistDetail         models.ArtistDetail
+		ConcertLocations     []ConcertLocation
 		ConcertLocationsJSON string
+		MapboxToken          string
+		YearsToFirstAlbum    int
 	}{
 		ArtistDetail:         artistDetail,
+		ConcertLocations:     concertLocations,
 		ConcertLocationsJSON: string(concertLocationsJSON),
+		MapboxToken:          mapboxToken,
+		YearsToFirstAlbum:    yearsToFirstAlbum,
 	}
 
 	// Render the artist detail page
-	err = templates.ExecuteTemplate(w, "artist_detail.html", data)
+	err = templates.ExecuteTemplate(w, "artist_detail.html", pageData)
 	if err != nil {
 		RenderError(w, http.StatusInternalServerError, "Error loading the artist detail page")
 	}
 }
-
-// SearchHandler handles search requests for artists
-func SearchHandler(w http.ResponseWriter, r *http.Request) {
-	query := strings.ToLower(r.URL.Query().Get("q"))
-	var results []map[string]string
-
-	if query != "" {
-		for _, artist := range artists {
-			types := artist.SearchResultType(query)
-			for _, resultType := range types {
-				results = append(results, map[string]string{
-					"name": resultType,
-					"id":   strconv.Itoa(artist.ID),
-				})
-			}
-		}
-
-		// Search by location
-		for _, relation := range relationsData.Index {
-			locationResults := relation.SearchArtistsByLocation(query, artists)
-			for _, result := range locationResults {
-				results = append(results, map[string]string{
-					"name": result,
-					"id":   strconv.Itoa(relation.ID),
-				})
-			}
-		}
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
-}
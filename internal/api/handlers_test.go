@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"groupie-tracker-search-bar/internal/catalog"
+	"groupie-tracker-search-bar/internal/geocode"
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// partialGeocoder answers from a table, failing the places listed in errs.
+// It mirrors selectiveGeocoder in the geocode tests, which cannot be shared:
+// those tests live inside package geocode.
+type partialGeocoder struct {
+	table geocode.Static
+	errs  map[string]error
+}
+
+func (g partialGeocoder) Geocode(ctx context.Context, place models.Place) (geocode.Coordinates, error) {
+	if err, ok := g.errs[place.Slug()]; ok {
+		return geocode.Coordinates{}, err
+	}
+	return g.table.Geocode(ctx, place)
+}
+
+// useCatalog serves c for the duration of the test
+func useCatalog(t *testing.T, c *catalog.Catalog) {
+	prev := current.Load()
+	current.Store(newState(c))
+	t.Cleanup(func() { current.Store(prev) })
+}
+
+// useGeocoder locates places with g for the duration of the test
+func useGeocoder(t *testing.T, g geocode.Geocoder) {
+	prev := geocoder
+	SetGeocoder(g)
+	t.Cleanup(func() { SetGeocoder(prev) })
+}
+
+var concertLocationsJSON = regexp.MustCompile(`(?s)<div id="ConcertLocationsJSON"[^>]*>(.*?)</div>`)
+
+func TestArtistDetailPartialGeocoding(t *testing.T) {
+	useCatalog(t, catalog.New(models.Dataset{
+		Artists: []models.Artist{{ID: 1, Name: "Queen", CreationDate: 1970, FirstAlbum: "14-12-1973"}},
+		Locations: models.LocationsData{Index: []models.Location{
+			{ID: 1, Locations: []string{"london-uk", "osaka-japan", "atlantis-nowhere"}},
+		}},
+	}))
+	useGeocoder(t, partialGeocoder{
+		table: geocode.Static{"london-uk": {Lat: 51.5074, Lng: -0.1278}},
+		errs:  map[string]error{"osaka-japan": errors.New("service unavailable")},
+	})
+
+	w := httptest.NewRecorder()
+	ArtistDetailHandler(w, httptest.NewRequest(http.MethodGet, "/artist/1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 with some locations unresolved, got %d", w.Code)
+	}
+	body := w.Body.String()
+
+	// The list marks the two places left off the map
+	list := body[strings.Index(body, `id="locations"`):]
+	list = list[:strings.Index(list, "</ul>")]
+	for _, name := range []string{"London, United Kingdom", "Osaka, Japan", "Atlantis, Nowhere"} {
+		if !strings.Contains(list, name) {
+			t.Errorf("expected %s in the locations list", name)
+		}
+	}
+	if n := strings.Count(list, "Not on map"); n != 2 {
+		t.Errorf("expected 2 locations marked as not on the map, got %d", n)
+	}
+
+	// The map data has coordinates for London and flags the others
+	match := concertLocationsJSON.FindStringSubmatch(body)
+	if match == nil {
+		t.Fatalf("expected the concert locations in the page")
+	}
+	var locations []struct {
+		LocationName string    `json:"locationName"`
+		Coordinates  []float64 `json:"coordinates"`
+		Unresolved   bool      `json:"unresolved"`
+	}
+	if err := json.Unmarshal([]byte(html.UnescapeString(match[1])), &locations); err != nil {
+		t.Fatalf("expected JSON concert locations, got %v", err)
+	}
+	if len(locations) != 3 {
+		t.Fatalf("expected 3 concert locations, got %+v", locations)
+	}
+	if l := locations[0]; l.LocationName != "London, United Kingdom" || l.Unresolved || len(l.Coordinates) != 2 || l.Coordinates[0] != -0.1278 {
+		t.Errorf("expected London with its coordinates, got %+v", l)
+	}
+	for _, l := range locations[1:] {
+		if !l.Unresolved || l.Coordinates != nil {
+			t.Errorf("expected %s to be unresolved without coordinates, got %+v", l.LocationName, l)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"groupie-tracker-search-bar/internal/catalog"
+	"groupie-tracker-search-bar/internal/fetch"
+	"groupie-tracker-search-bar/internal/validate"
+)
+
+// loadState fetches a fresh dataset from source, checks its integrity according
+// to policy and builds the catalog and search index from it
+func loadState(ctx context.Context, source fetch.DataSource, policy validate.Policy) (*state, error) {
+	dataset, err := fetch.FetchAllData(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	dataset, report, err := validate.Apply(dataset, policy)
+	if err != nil {
+		return nil, err
+	}
+	if !report.OK() {
+		log.Printf("Loaded data from %v with %s (policy %s)", source, report.Summary(), policy)
+	}
+	if len(dataset.Artists) == 0 {
+		return nil, fmt.Errorf("dataset has no artists")
+	}
+	return newState(catalog.New(dataset)), nil
+}
+
+// RefreshData reloads the data from source and swaps it in atomically.
+// On failure the data currently being served is left untouched.
+func RefreshData(ctx context.Context, source fetch.DataSource, policy validate.Policy) error {
+	s, err := loadState(ctx, source, policy)
+	if err != nil {
+		return err
+	}
+	current.Store(s)
+	return nil
+}
+
+// StartRefresher calls RefreshData every interval in the background until ctx is done.
+func StartRefresher(ctx context.Context, source fetch.DataSource, policy validate.Policy, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := RefreshData(ctx, source, policy); err != nil {
+					log.Printf("Error refreshing data, keeping the current data: %v", err)
+					continue
+				}
+				log.Printf("Refreshed data from %v", source)
+			}
+		}
+	}()
+}
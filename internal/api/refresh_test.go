@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"groupie-tracker-search-bar/internal/catalog"
+	"groupie-tracker-search-bar/internal/fetch"
+	"groupie-tracker-search-bar/internal/models"
+	"groupie-tracker-search-bar/internal/search"
+	"groupie-tracker-search-bar/internal/validate"
+)
+
+// testSource serves artists as the whole dataset. With complete set, artist 1
+// gets a locations, dates and relation entry; otherwise no artist has any.
+func testSource(artists string, complete bool) fetch.FSSource {
+	locations, dates, relation := `{"index": []}`, `{"index": []}`, `{"index": []}`
+	if complete {
+		locations = `{"index": [{"id": 1, "locations": ["london-uk"]}]}`
+		dates = `{"index": [{"id": 1, "dates": ["01-03-1974"]}]}`
+		relation = `{"index": [{"id": 1, "datesLocations": {"london-uk": ["01-03-1974"]}}]}`
+	}
+	return fetch.FSSource{Name: "test", FS: fstest.MapFS{
+		"artists.json":   {Data: []byte(artists)},
+		"locations.json": {Data: []byte(locations)},
+		"dates.json":     {Data: []byte(dates)},
+		"relation.json":  {Data: []byte(relation)},
+	}}
+}
+
+const mercuryRev = `[{"id": 1, "name": "Mercury Rev", "members": ["Jonathan Donahue"], "creationDate": 1989, "firstAlbum": "01-06-1991"}]`
+
+// serving serves a catalog of Queen, returning the state in place
+func serving(t *testing.T) *state {
+	useCatalog(t, catalog.New(models.Dataset{
+		Artists: []models.Artist{{ID: 1, Name: "Queen", Members: []string{"Freddie Mercury"}}},
+	}))
+	return currentState()
+}
+
+func TestRefreshDataKeepsStateOnFailure(t *testing.T) {
+	before := serving(t)
+
+	// The source cannot be read
+	failing := fetch.FSSource{Name: "empty", FS: fstest.MapFS{}}
+	if err := RefreshData(context.Background(), failing, validate.Degrade); err == nil {
+		t.Errorf("expected an error from a source with no files")
+	}
+	if currentState() != before {
+		t.Errorf("expected a failed load to leave the data in place")
+	}
+
+	// The dataset is read but rejected by the policy
+	if err := RefreshData(context.Background(), testSource(mercuryRev, false), validate.Strict); err == nil {
+		t.Errorf("expected the strict policy to reject artists without entries")
+	}
+	if currentState() != before {
+		t.Errorf("expected a rejected dataset to leave the data in place")
+	}
+
+	// The same dataset is served when the policy lets it through
+	if err := RefreshData(context.Background(), testSource(mercuryRev, false), validate.Warn); err != nil {
+		t.Errorf("expected the warn policy to accept the dataset, got %v", err)
+	}
+}
+
+func TestRefreshDataSwapsState(t *testing.T) {
+	serving(t)
+
+	if err := RefreshData(context.Background(), testSource(mercuryRev, true), validate.Strict); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	s := currentState()
+	if detail, ok := s.catalog.Detail(1); !ok || detail.Artist.Name != "Mercury Rev" {
+		t.Errorf("expected the catalog to hold the new data, got %+v", detail.Artist)
+	}
+	// The engine comes from the same load: it finds the new artist, not the old one
+	if results := s.search.Search("mercury"); len(results) != 1 || results[0].ArtistName != "Mercury Rev" || results[0].Field != search.FieldArtist {
+		t.Errorf("expected the search engine to match the new catalog, got %+v", results)
+	}
+	if results := s.search.Search("queen"); len(results) != 0 {
+		t.Errorf("expected the old data to be gone from search, got %+v", results)
+	}
+}
+
+func TestStartRefresher(t *testing.T) {
+	before := serving(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	StartRefresher(ctx, testSource(mercuryRev, true), validate.Strict, 10*time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for currentState() == before {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the refresher to swap in the new data")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
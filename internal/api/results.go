@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"groupie-tracker-search-bar/internal/search"
+)
+
+// categoryNames label the categories on the results page
+var categoryNames = map[search.Field]string{
+	search.FieldArtist:       "Artist",
+	search.FieldMember:       "Member",
+	search.FieldLocation:     "Location",
+	search.FieldConcertDate:  "Concert date",
+	search.FieldFirstAlbum:   "First album",
+	search.FieldCreationDate: "Creation date",
+}
+
+// ResultsHandler renders the full results of a search, grouped by category, with pagination
+// and category filters. It takes the same q and category parameters as /search, and page and limit
+// like the artists listing.
+func ResultsHandler(w http.ResponseWriter, r *http.Request) {
+	rawQuery := r.URL.Query().Get("q")
+	fields := parseCategories(r.URL.Query())
+
+	// Get 'page' and 'limit' query parameters, defaulting to the first page and capping
+	// the limit like /search does
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	type resultItem struct {
+		SearchResult
+		CategoryName string
+		Segments     []search.Segment
+	}
+	type resultGroup struct {
+		Name    string
+		Results []resultItem
+	}
+	type categoryOption struct {
+		Value   search.Field
+		Name    string
+		Checked bool
+	}
+	pageData := struct {
+		Query       string
+		Error       string
+		Groups      []resultGroup
+		Total       int
+		TotalPages  int
+		CurrentPage int
+		HasPrevPage bool
+		HasNextPage bool
+		PrevPageURL string
+		NextPageURL string
+		Categories  []categoryOption
+	}{
+		Query:       rawQuery,
+		TotalPages:  1,
+		CurrentPage: 1,
+	}
+	for _, f := range search.Fields {
+		pageData.Categories = append(pageData.Categories, categoryOption{Value: f, Name: categoryNames[f], Checked: slices.Contains(fields, f)})
+	}
+
+	status := http.StatusOK
+	query, err := search.ParseQuery(rawQuery)
+	if err != nil {
+		status = http.StatusBadRequest
+		pageData.Error = err.Error()
+	} else if !query.IsZero() {
+		results, total := search.Paginate(currentState().search.Find(query), search.Page{Fields: fields})
+
+		// Calculate total pages, keeping at least one page for an empty result
+		totalPages := max((total+limit-1)/limit, 1)
+		page = min(page, totalPages)
+		results, _ = search.Paginate(results, search.Page{Offset: (page - 1) * limit, Limit: limit})
+
+		for _, g := range search.GroupResults(results) {
+			group := resultGroup{Name: g.Name}
+			for _, result := range g.Results {
+				group.Results = append(group.Results, resultItem{
+					SearchResult: NewSearchResult(result),
+					CategoryName: categoryNames[result.Field],
+					Segments:     result.Segments(),
+				})
+			}
+			pageData.Groups = append(pageData.Groups, group)
+		}
+		pageData.Total = total
+		pageData.TotalPages = totalPages
+		pageData.CurrentPage = page
+		pageData.HasPrevPage = page > 1
+		pageData.HasNextPage = page < totalPages
+		pageData.PrevPageURL = resultsPageURL(rawQuery, fields, page-1, limit)
+		pageData.NextPageURL = resultsPageURL(rawQuery, fields, page+1, limit)
+	}
+
+	w.WriteHeader(status)
+	err = templates.ExecuteTemplate(w, "results.html", pageData)
+	if err != nil {
+		RenderError(w, http.StatusInternalServerError, "Error loading the search results page")
+	}
+}
+
+// resultsPageURL links to a page of search results, keeping the query, the category filters and a non-default limit
+func resultsPageURL(query string, fields []search.Field, page, limit int) string {
+	values := url.Values{}
+	values.Set("q", query)
+	for _, f := range fields {
+		values.Add("category", string(f))
+	}
+	values.Set("page", strconv.Itoa(page))
+	if limit != defaultSearchLimit {
+		values.Set("limit", strconv.Itoa(limit))
+	}
+	return "/results?" + values.Encode()
+}
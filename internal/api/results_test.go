@@ -0,0 +1,95 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"groupie-tracker-search-bar/internal/catalog"
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// bands is a catalog of n artists named "Band 1" to "Band n"
+func bands(n int) *catalog.Catalog {
+	var d models.Dataset
+	for i := 1; i <= n; i++ {
+		d.Artists = append(d.Artists, models.Artist{ID: i, Name: fmt.Sprintf("Band %d", i)})
+	}
+	return catalog.New(d)
+}
+
+// getResults renders /results with the given query string
+func getResults(t *testing.T, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	ResultsHandler(w, httptest.NewRequest(http.MethodGet, "/results?"+query, nil))
+	return w
+}
+
+var pageLink = regexp.MustCompile(`<a href="([^"]*)" class="pagination-button">(Previous|Next)</a>`)
+
+func TestResultsMalformedQuery(t *testing.T) {
+	useCatalog(t, bands(3))
+
+	w := getResults(t, "q="+url.QueryEscape("year:seventies"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a malformed query, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "year:") {
+		t.Errorf("expected the page to explain the error")
+	}
+}
+
+func TestResultsPagination(t *testing.T) {
+	useCatalog(t, bands(5))
+
+	// Past the last page, the last page is shown
+	w := getResults(t, "q=band&category=artist&limit=2&page=99")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, "Page 3 of 3") {
+		t.Errorf("expected the page to be clamped to 3 of 3")
+	}
+	if n := strings.Count(body, `href="/artist/`); n != 1 {
+		t.Errorf("expected the 1 result of the last page, got %d", n)
+	}
+
+	links := pageLink.FindAllStringSubmatch(body, -1)
+	if len(links) != 1 || links[0][2] != "Previous" {
+		t.Fatalf("expected only a Previous link on the last page, got %v", links)
+	}
+	prev, err := url.Parse(links[0][1])
+	if err != nil {
+		t.Fatalf("invalid Previous link %q: %v", links[0][1], err)
+	}
+	values := prev.Query()
+	if prev.Path != "/results" || values.Get("q") != "band" || values.Get("category") != "artist" ||
+		values.Get("page") != "2" || values.Get("limit") != "2" {
+		t.Errorf("expected the Previous link to keep the query, category and limit, got %q", links[0][1])
+	}
+
+	// The limit is capped like /search
+	useCatalog(t, bands(maxSearchLimit+1))
+	w = getResults(t, "q=band&limit=100000")
+	if !strings.Contains(w.Body.String(), "Page 1 of 2") {
+		t.Errorf("expected the limit to be capped at %d results a page", maxSearchLimit)
+	}
+}
+
+func TestResultsEscapesQuery(t *testing.T) {
+	useCatalog(t, bands(1))
+
+	w := getResults(t, "q="+url.QueryEscape(`"><script>alert(1)</script>`))
+	body := w.Body.String()
+	if strings.Contains(body, "<script>alert(1)") {
+		t.Errorf("expected the query not to be echoed unescaped")
+	}
+	if !strings.Contains(body, `value="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;"`) {
+		t.Errorf("expected the query to be escaped in the search field")
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"groupie-tracker-search-bar/internal/search"
+)
+
+// SearchResult is one entry of the /search response.
+type SearchResult struct {
+	Category   search.Field    `json:"category"`   // the part of the artist that matched
+	Text       string          `json:"text"`       // the matched text, e.g. a member name or a place
+	Highlights []search.Span   `json:"highlights"` // rune ranges of Text that matched the query
+	ArtistID   int             `json:"artist_id"`
+	ArtistName string          `json:"artist_name"`
+	Concerts   []SearchConcert `json:"concerts,omitempty"` // every show matched, in date order, for concert_date results
+	URL        string          `json:"url"`                // where the result leads on the site
+	Label      string          `json:"label"`              // a ready-made description, e.g. "Freddie Mercury - member of Queen"
+}
+
+// SearchConcert tells where and when an artist played, for concert_date results.
+type SearchConcert struct {
+	Date     string `json:"date"`     // YYYY-MM-DD
+	Location string `json:"location"` // upstream slug, e.g. "los_angeles-usa"
+	Place    string `json:"place"`    // display name, e.g. "Los Angeles, United States"
+}
+
+// resultSections maps each category to the section of the artist page that shows it
+var resultSections = map[search.Field]string{
+	search.FieldMember:       "members",
+	search.FieldFirstAlbum:   "album",
+	search.FieldCreationDate: "about",
+	search.FieldLocation:     "locations",
+	search.FieldConcertDate:  "tour-dates",
+}
+
+// NewSearchResult describes r for clients of /search
+func NewSearchResult(r search.Result) SearchResult {
+	url := "/artist/" + strconv.Itoa(r.ArtistID)
+	if section, ok := resultSections[r.Field]; ok {
+		url += "#" + section
+	}
+	highlights := r.Highlights
+	if highlights == nil {
+		highlights = []search.Span{}
+	}
+	var concerts []SearchConcert
+	for _, c := range r.Concerts {
+		concerts = append(concerts, SearchConcert{Date: c.ISODate(), Location: c.Location, Place: c.Place.String()})
+	}
+	return SearchResult{
+		Category:   r.Field,
+		Text:       r.Text,
+		Highlights: highlights,
+		ArtistID:   r.ArtistID,
+		ArtistName: r.ArtistName,
+		Concerts:   concerts,
+		URL:        url,
+		Label:      r.Label(),
+	}
+}
+
+// SearchResponse is the /search response: one page of the results and how many there are in all.
+type SearchResponse struct {
+	Total   int            `json:"total"`
+	Offset  int            `json:"offset"`
+	Limit   int            `json:"limit"`
+	Results []SearchResult `json:"results"`
+}
+
+// SearchError is the /search response to a malformed query.
+type SearchError struct {
+	Error string `json:"error"`
+}
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// parseSearchPage reads the limit, offset, per_category and category query parameters.
+// Invalid values are ignored, the same way the artists pagination parameters are.
+func parseSearchPage(query url.Values) search.Page {
+	page := search.Page{Limit: defaultSearchLimit}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit >= 1 {
+		page.Limit = min(limit, maxSearchLimit)
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset >= 0 {
+		page.Offset = offset
+	}
+	if perCategory, err := strconv.Atoi(query.Get("per_category")); err == nil && perCategory >= 1 {
+		page.PerCategory = perCategory
+	}
+	page.Fields = parseCategories(query)
+	return page
+}
+
+// parseCategories reads the repeated category query parameter, ignoring unknown categories
+func parseCategories(query url.Values) []search.Field {
+	var fields []search.Field
+	for _, value := range query["category"] {
+		if search.IsField(value) && !slices.Contains(fields, search.Field(value)) {
+			fields = append(fields, search.Field(value))
+		}
+	}
+	return fields
+}
+
+// SearchHandler handles search requests for artists, returning the best match per artist, most relevant first.
+// The query may restrict fields with qualifiers such as member:freddie; a malformed one is answered with a 400.
+// Results are paged with limit and offset, category (repeated) keeps only those categories,
+// and per_category caps how many of each category are kept.
+func SearchHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query, err := search.ParseQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(SearchError{Error: err.Error()})
+		return
+	}
+
+	page := parseSearchPage(r.URL.Query())
+	results, total := search.Paginate(currentState().search.Find(query), page)
+
+	response := SearchResponse{Total: total, Offset: page.Offset, Limit: page.Limit, Results: []SearchResult{}}
+	for _, result := range results {
+		response.Results = append(response.Results, NewSearchResult(result))
+	}
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,134 @@
+package catalog
+
+import (
+	"sort"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// Entry holds everything known about one artist.
+type Entry struct {
+	Artist   models.Artist
+	Location models.Location
+	Date     models.Date
+	Relation models.Relation
+	Concerts []models.Concert // chronological, built from Relation and Date
+}
+
+// Catalog is an immutable view of a dataset keyed by artist ID.
+// Lookups are O(1) and iteration follows the order of the artists in the dataset.
+// A Catalog must not be modified after New returns, so it can be shared
+// between concurrent requests without locking.
+type Catalog struct {
+	order   []int
+	entries map[int]Entry
+	places  []models.Place
+}
+
+// New builds a catalog from d, matching locations, dates and relations to
+// artists by ID rather than by position. Artists without a matching entry get
+// an empty one; entries without an artist are ignored.
+func New(d models.Dataset) *Catalog {
+	c := &Catalog{entries: make(map[int]Entry, len(d.Artists))}
+	for _, artist := range d.Artists {
+		if _, seen := c.entries[artist.ID]; seen {
+			continue
+		}
+		c.order = append(c.order, artist.ID)
+		c.entries[artist.ID] = Entry{
+			Artist:   artist,
+			Location: models.Location{ID: artist.ID},
+			Date:     models.Date{ID: artist.ID},
+			Relation: models.Relation{ID: artist.ID},
+		}
+	}
+
+	for _, location := range d.Locations.Index {
+		if entry, ok := c.entries[location.ID]; ok {
+			entry.Location = location
+			c.entries[location.ID] = entry
+		}
+	}
+	for _, date := range d.Dates.Index {
+		if entry, ok := c.entries[date.ID]; ok {
+			entry.Date = date
+			c.entries[date.ID] = entry
+		}
+	}
+	for _, relation := range d.Relations.Index {
+		if entry, ok := c.entries[relation.ID]; ok {
+			entry.Relation = relation
+			c.entries[relation.ID] = entry
+		}
+	}
+	seen := map[string]bool{}
+	for id, entry := range c.entries {
+		entry.Concerts = models.Concerts(entry.Relation, entry.Date)
+		c.entries[id] = entry
+		for _, location := range entry.Location.Locations {
+			if !seen[location] {
+				seen[location] = true
+				c.places = append(c.places, models.ParsePlace(location))
+			}
+		}
+	}
+	sort.Slice(c.places, func(i, j int) bool {
+		return c.places[i].String() < c.places[j].String()
+	})
+	return c
+}
+
+// Len returns the number of artists in the catalog.
+func (c *Catalog) Len() int {
+	return len(c.order)
+}
+
+// Entry returns everything known about the artist with the given ID.
+func (c *Catalog) Entry(id int) (Entry, bool) {
+	entry, ok := c.entries[id]
+	return entry, ok
+}
+
+// Artist returns the artist with the given ID.
+func (c *Catalog) Artist(id int) (models.Artist, bool) {
+	entry, ok := c.entries[id]
+	return entry.Artist, ok
+}
+
+// Detail returns the artist with the given ID together with its locations, dates and relations.
+func (c *Catalog) Detail(id int) (models.ArtistDetail, bool) {
+	entry, ok := c.entries[id]
+	if !ok {
+		return models.ArtistDetail{}, false
+	}
+	return models.ArtistDetail{
+		Artist:    entry.Artist,
+		Locations: entry.Location,
+		Dates:     entry.Date,
+		Relations: entry.Relation,
+		Concerts:  entry.Concerts,
+	}, true
+}
+
+// Artists returns every artist in catalog order.
+func (c *Catalog) Artists() []models.Artist {
+	artists := make([]models.Artist, 0, len(c.order))
+	for _, id := range c.order {
+		artists = append(artists, c.entries[id].Artist)
+	}
+	return artists
+}
+
+// Entries returns every entry in catalog order.
+func (c *Catalog) Entries() []Entry {
+	entries := make([]Entry, 0, len(c.order))
+	for _, id := range c.order {
+		entries = append(entries, c.entries[id])
+	}
+	return entries
+}
+
+// Places returns every distinct concert location in the catalog, sorted by display name.
+func (c *Catalog) Places() []models.Place {
+	return append([]models.Place(nil), c.places...)
+}
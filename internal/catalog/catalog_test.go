@@ -0,0 +1,74 @@
+package catalog
+
+import (
+	"reflect"
+	"testing"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+func TestNew(t *testing.T) {
+	c := New(models.Dataset{
+		Artists: []models.Artist{
+			{ID: 7, Name: "Artist 7"},
+			{ID: 3, Name: "Artist 3"},
+		},
+		// Upstream order differs between collections on purpose.
+		Locations: models.LocationsData{Index: []models.Location{
+			{ID: 3, Locations: []string{"london-uk"}},
+			{ID: 7, Locations: []string{"paris-france"}},
+		}},
+		Relations: models.RelationsData{Index: []models.Relation{
+			{ID: 7, DatesLocations: map[string][]string{"paris-france": {"01-01-2020"}}},
+			{ID: 99, DatesLocations: map[string][]string{"berlin-germany": {"02-01-2020"}}},
+		}},
+	})
+
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 artists, got %d", c.Len())
+	}
+
+	artists := c.Artists()
+	if artists[0].ID != 7 || artists[1].ID != 3 {
+		t.Errorf("expected artists in dataset order [7 3], got [%d %d]", artists[0].ID, artists[1].ID)
+	}
+
+	detail, ok := c.Detail(7)
+	if !ok {
+		t.Fatalf("expected artist 7 to be found")
+	}
+	if detail.Locations.Locations[0] != "paris-france" {
+		t.Errorf("expected artist 7 to be matched with its own locations, got %v", detail.Locations.Locations)
+	}
+	if len(detail.Relations.DatesLocations) != 1 {
+		t.Errorf("expected artist 7 to be matched with its own relation, got %v", detail.Relations.DatesLocations)
+	}
+
+	entry, _ := c.Entry(3)
+	if entry.Date.ID != 3 || len(entry.Date.Dates) != 0 {
+		t.Errorf("expected an empty dates entry for artist 3, got %v", entry.Date)
+	}
+
+	if _, ok := c.Artist(99); ok {
+		t.Errorf("expected the relation without an artist to be ignored")
+	}
+}
+
+func TestPlaces(t *testing.T) {
+	c := New(models.Dataset{
+		Artists: []models.Artist{{ID: 1}, {ID: 2}},
+		Locations: models.LocationsData{Index: []models.Location{
+			{ID: 1, Locations: []string{"paris-france", "london-uk"}},
+			{ID: 2, Locations: []string{"london-uk", "berlin-germany"}},
+		}},
+	})
+
+	var names []string
+	for _, place := range c.Places() {
+		names = append(names, place.String())
+	}
+	expected := []string{"Berlin, Germany", "London, United Kingdom", "Paris, France"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,71 @@
+package catalog
+
+import (
+	"strings"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// Filter selects artists of the catalog. The zero value matches every artist.
+type Filter struct {
+	Created  models.YearRange // creation year
+	Album    models.YearRange // first album year
+	Members  []int            // accepted member counts; empty accepts any
+	Location string           // a slug, or part of the display name of a place the artist played in
+}
+
+// IsZero reports whether no filter is set.
+func (f Filter) IsZero() bool {
+	return f.Created.IsZero() && f.Album.IsZero() && len(f.Members) == 0 && f.Location == ""
+}
+
+// Match reports whether entry passes every filter.
+func (f Filter) Match(entry Entry) bool {
+	artist := entry.Artist
+	if !f.Created.Contains(artist.CreationDate) {
+		return false
+	}
+	if !f.Album.Contains(artist.FirstAlbumYear()) {
+		return false
+	}
+	if len(f.Members) > 0 && !f.HasMembers(len(artist.Members)) {
+		return false
+	}
+	if f.Location != "" && !playedIn(entry.Location.Locations, f.Location) {
+		return false
+	}
+	return true
+}
+
+// HasMembers reports whether the member count n is accepted.
+func (f Filter) HasMembers(n int) bool {
+	for _, m := range f.Members {
+		if m == n {
+			return true
+		}
+	}
+	return false
+}
+
+// playedIn reports whether one of the location slugs equals location or
+// contains it in its display name
+func playedIn(locations []string, location string) bool {
+	lower := strings.ToLower(location)
+	for _, slug := range locations {
+		if slug == lower || strings.Contains(strings.ToLower(models.ParsePlace(slug).String()), lower) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the entries matching f in catalog order.
+func (c *Catalog) Filter(f Filter) []Entry {
+	var matched []Entry
+	for _, id := range c.order {
+		if entry := c.entries[id]; f.Match(entry) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,54 @@
+package catalog
+
+import (
+	"testing"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+func TestFilter(t *testing.T) {
+	c := New(models.Dataset{
+		Artists: []models.Artist{
+			{ID: 1, Name: "Queen", Members: make([]string, 7), CreationDate: 1970, FirstAlbum: "14-12-1973"},
+			{ID: 2, Name: "SOJA", Members: make([]string, 8), CreationDate: 1997, FirstAlbum: "05-06-2002"},
+			{ID: 3, Name: "Pink Floyd", Members: make([]string, 5), CreationDate: 1965, FirstAlbum: "05-08-1967"},
+		},
+		Locations: models.LocationsData{Index: []models.Location{
+			{ID: 1, Locations: []string{"los_angeles-usa", "osaka-japan"}},
+			{ID: 2, Locations: []string{"noumea-new_caledonia"}},
+			{ID: 3, Locations: []string{"london-uk"}},
+		}},
+	})
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []int
+	}{
+		{"no filter", Filter{}, []int{1, 2, 3}},
+		{"creation range", Filter{Created: models.YearRange{From: 1960, To: 1970}}, []int{1, 3}},
+		{"album from", Filter{Album: models.YearRange{From: 1970}}, []int{1, 2}},
+		{"members", Filter{Members: []int{5, 8}}, []int{2, 3}},
+		{"location slug", Filter{Location: "london-uk"}, []int{3}},
+		{"location name", Filter{Location: "United States"}, []int{1}},
+		{"combined", Filter{Created: models.YearRange{To: 1980}, Members: []int{7}, Location: "osaka"}, []int{1}},
+		{"nothing", Filter{Album: models.YearRange{From: 2010}}, nil},
+	}
+
+	for _, tt := range tests {
+		var ids []int
+		for _, entry := range c.Filter(tt.filter) {
+			ids = append(ids, entry.Artist.ID)
+		}
+		if len(ids) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, ids)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, ids)
+				break
+			}
+		}
+	}
+}
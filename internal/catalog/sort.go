@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"cmp"
+	"sort"
+
+	"groupie-tracker-search-bar/internal/models"
+	utils "groupie-tracker-search-bar/internal/utilities"
+)
+
+// Sort keys accepted by SortEntries.
+const (
+	SortName     = "name"     // artist name, ignoring case and diacritics
+	SortCreated  = "created"  // creation year
+	SortAlbum    = "album"    // first album date; unknown dates always sort last
+	SortMembers  = "members"  // number of members
+	SortConcerts = "concerts" // number of concerts
+)
+
+// IsSortKey reports whether key is one of the sort keys above.
+func IsSortKey(key string) bool {
+	switch key {
+	case SortName, SortCreated, SortAlbum, SortMembers, SortConcerts:
+		return true
+	}
+	return false
+}
+
+// SortEntries sorts entries in place by key. Ties are broken by artist ID in
+// ascending order whatever the direction, so the order is identical on every
+// request and pages never overlap. An unknown key leaves entries unchanged.
+func SortEntries(entries []Entry, key string, descending bool) {
+	if !IsSortKey(key) {
+		return
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if key == SortAlbum {
+			_, okA := a.Artist.FirstAlbumDate()
+			_, okB := b.Artist.FirstAlbumDate()
+			if okA != okB {
+				return okA
+			}
+		}
+		c := compareBy(key, a, b)
+		if descending {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+		return a.Artist.ID < b.Artist.ID
+	})
+}
+
+func compareBy(key string, a, b Entry) int {
+	switch key {
+	case SortName:
+		return utils.CompareFolded(a.Artist.Name, b.Artist.Name)
+	case SortCreated:
+		return cmp.Compare(a.Artist.CreationDate, b.Artist.CreationDate)
+	case SortAlbum:
+		return models.CompareFirstAlbum(a.Artist, b.Artist)
+	case SortMembers:
+		return cmp.Compare(len(a.Artist.Members), len(b.Artist.Members))
+	case SortConcerts:
+		return cmp.Compare(len(a.Concerts), len(b.Concerts))
+	}
+	return 0
+}
@@ -0,0 +1,53 @@
+package catalog
+
+import (
+	"testing"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+func TestSortEntries(t *testing.T) {
+	c := New(models.Dataset{
+		Artists: []models.Artist{
+			{ID: 1, Name: "queen", Members: make([]string, 7), CreationDate: 1970, FirstAlbum: "14-12-1973"},
+			{ID: 2, Name: "Érasure", Members: make([]string, 2), CreationDate: 1985, FirstAlbum: "unknown"},
+			{ID: 3, Name: "Pink Floyd", Members: make([]string, 5), CreationDate: 1965, FirstAlbum: "05-08-1967"},
+			{ID: 4, Name: "ABBA", Members: make([]string, 4), CreationDate: 1970, FirstAlbum: "30-03-1973"},
+		},
+		Relations: models.RelationsData{Index: []models.Relation{
+			{ID: 3, DatesLocations: map[string][]string{"london-uk": {"01-01-2020", "02-01-2020"}}},
+			{ID: 4, DatesLocations: map[string][]string{"paris-france": {"01-01-2020"}}},
+		}},
+	})
+
+	tests := []struct {
+		key        string
+		descending bool
+		expected   []int
+	}{
+		{SortName, false, []int{4, 2, 3, 1}},
+		{SortName, true, []int{1, 3, 2, 4}},
+		{SortCreated, false, []int{3, 1, 4, 2}},
+		{SortCreated, true, []int{2, 1, 4, 3}}, // 1 and 4 tie and keep ID order
+		{SortAlbum, false, []int{3, 4, 1, 2}},
+		{SortAlbum, true, []int{1, 4, 3, 2}}, // unknown date stays last
+		{SortMembers, true, []int{1, 3, 4, 2}},
+		{SortConcerts, true, []int{3, 4, 1, 2}},
+		{"unknown", false, []int{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		entries := c.Entries()
+		SortEntries(entries, tt.key, tt.descending)
+		for i, entry := range entries {
+			if entry.Artist.ID != tt.expected[i] {
+				var ids []int
+				for _, e := range entries {
+					ids = append(ids, e.Artist.ID)
+				}
+				t.Errorf("sort %s (descending %v): expected %v, got %v", tt.key, tt.descending, tt.expected, ids)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package fetch
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// EndpointError reports which endpoint of a data source failed to load and why.
+type EndpointError struct {
+	Endpoint string
+	Source   string
+	Err      error
+}
+
+func (e *EndpointError) Error() string {
+	return fmt.Sprintf("loading %s from %s: %v", e.Endpoint, e.Source, e.Err)
+}
+
+func (e *EndpointError) Unwrap() error {
+	return e.Err
+}
+
+// UpstreamStatusError is returned when the upstream answers with a non-2xx status.
+// Body holds the start of the response to help diagnose error pages.
+type UpstreamStatusError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *UpstreamStatusError) Error() string {
+	return fmt.Sprintf("%s answered %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// DecodeError is returned when a response or file cannot be turned into the
+// expected models: wrong content type, oversized body, malformed JSON, or
+// unknown fields in strict mode.
+type DecodeError struct {
+	URL string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decoding %s: %v", e.URL, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
@@ -1,111 +1,125 @@
 package fetch
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
-	"net/url"
-	"strings"
 	"sync"
-	"time"
 
 	"groupie-tracker-search-bar/internal/models"
 )
 
-const mapboxToken = "pk.eyJ1IjoiYXRob29oIiwiYSI6ImNtMWY2N3prZjJsN3MybHNjMWd3bThzOXcifQ.HNgAHQBkzGdrnuS1MtwYlQ"
+// Client is the HTTP client shared by every upstream request.
+var Client = &http.Client{}
 
-type GeocodeResponse struct {
-	Features []struct {
-		Center []float64 `json:"center"`
-	} `json:"features"`
+// DefaultMaxBodySize caps upstream responses when Options.MaxBodySize is zero.
+const DefaultMaxBodySize = 10 << 20
+
+// Options controls how upstream payloads are validated.
+type Options struct {
+	MaxBodySize int64       // larger payloads are rejected; zero means DefaultMaxBodySize
+	Strict      bool        // reject JSON fields the models do not declare
+	Header      http.Header // extra request headers, e.g. a User-Agent some APIs require
+}
+
+// FetchData requests url with the shared Client and decodes the JSON response into target
+// using the default Options. The request is abandoned when ctx is cancelled or its deadline passes.
+func FetchData(ctx context.Context, url string, target interface{}) error {
+	return FetchDataWithOptions(ctx, url, target, Options{})
 }
 
-func FetchData(url string, target interface{}) error {
-	resp, err := http.Get(url)
+// FetchDataWithOptions is FetchData with explicit validation options. A non-2xx
+// status is returned as an *UpstreamStatusError; a body that is not JSON, too large
+// or does not match target is returned as a *DecodeError.
+func FetchDataWithOptions(ctx context.Context, url string, target interface{}, opts Options) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, values := range opts.Header {
+		req.Header[key] = values
+	}
+	resp, err := Client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	return json.NewDecoder(resp.Body).Decode(target)
-}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &UpstreamStatusError{URL: url, StatusCode: resp.StatusCode, Body: string(snippet)}
+	}
 
-// In-memory cache for geocoding results
-var geocodeCache = make(map[string][]float64)
-var cacheMutex sync.RWMutex
+	// Some mirrors label JSON as text/plain, so only reject HTML error pages outright.
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "text/html" || mediaType == "application/xhtml+xml" {
+		return &DecodeError{URL: url, Err: fmt.Errorf("unexpected content type %q", contentType)}
+	}
 
-// formatLocation takes a location string like 'auckland-new_zealand'
-// and returns a properly formatted string 'auckland, new zealand'.
-func formatLocation(location string) string {
-	formattedLocation := strings.ReplaceAll(location, "-", ", ")        // Replace hyphens with ', '
-	formattedLocation = strings.ReplaceAll(formattedLocation, "_", " ") // Replace underscores with spaces
-	return formattedLocation
+	return decodeJSON(url, resp.Body, target, opts)
 }
 
-func FetchAllData() ([]models.Artist, models.LocationsData, models.DatesData, models.RelationsData, error) {
-	var artists []models.Artist
-	var locationsData models.LocationsData
-	var datesData models.DatesData
-	var relationsData models.RelationsData
+// decodeJSON reads at most opts.MaxBodySize bytes from r and decodes them into target
+func decodeJSON(name string, r io.Reader, target interface{}, opts Options) error {
+	limit := opts.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
 
-	err := FetchData("https://groupietrackers.herokuapp.com/api/artists", &artists)
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
 	if err != nil {
-		return nil, models.LocationsData{}, models.DatesData{}, models.RelationsData{}, err
+		return &DecodeError{URL: name, Err: err}
 	}
-	err = FetchData("https://groupietrackers.herokuapp.com/api/locations", &locationsData)
-	if err != nil {
-		return nil, models.LocationsData{}, models.DatesData{}, models.RelationsData{}, err
+	if int64(len(body)) > limit {
+		return &DecodeError{URL: name, Err: fmt.Errorf("body exceeds %d bytes", limit)}
 	}
-	err = FetchData("https://groupietrackers.herokuapp.com/api/dates", &datesData)
-	if err != nil {
-		return nil, models.LocationsData{}, models.DatesData{}, models.RelationsData{}, err
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if opts.Strict {
+		decoder.DisallowUnknownFields()
 	}
-	err = FetchData("https://groupietrackers.herokuapp.com/api/relation", &relationsData)
-	if err != nil {
-		return nil, models.LocationsData{}, models.DatesData{}, models.RelationsData{}, err
+	if err := decoder.Decode(target); err != nil {
+		return &DecodeError{URL: name, Err: err}
 	}
-
-	return artists, locationsData, datesData, relationsData, nil
+	return nil
 }
 
-// GeocodeLocation takes a raw location, formats it, and returns geographic coordinates.
-// Implements caching to avoid redundant requests.
-func GeocodeLocation(rawLocation string) ([]float64, error) {
-	formattedLocation := formatLocation(rawLocation)
-
-	// Check if the location is cached
-	cacheMutex.RLock()
-	if cachedCoords, found := geocodeCache[formattedLocation]; found {
-		cacheMutex.RUnlock()
-		return cachedCoords, nil
-	}
-	cacheMutex.RUnlock()
-
-	var geocodeResponse GeocodeResponse
-	var err error
-
-	// Retry logic in case of network issues
-	for attempts := 0; attempts < 3; attempts++ {
-		geocodeURL := fmt.Sprintf("https://api.mapbox.com/geocoding/v5/mapbox.places/%s.json?access_token=%s", url.QueryEscape(formattedLocation), mapboxToken)
-		resp, err := http.Get(geocodeURL)
-		if err == nil && resp.StatusCode == 200 {
-			defer resp.Body.Close()
-			err = json.NewDecoder(resp.Body).Decode(&geocodeResponse)
-			if err == nil && len(geocodeResponse.Features) > 0 {
-				coords := geocodeResponse.Features[0].Center
-
-				// Cache the coordinates for future use
-				cacheMutex.Lock()
-				geocodeCache[formattedLocation] = coords
-				cacheMutex.Unlock()
-
-				return coords, nil
-			}
+// FetchAllData loads artists, locations, dates and relations from source concurrently.
+// The first endpoint to fail cancels the others and is returned as an *EndpointError.
+func FetchAllData(ctx context.Context, source DataSource) (models.Dataset, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var dataset models.Dataset
+	var wg sync.WaitGroup
+	var failOnce sync.Once
+	var firstErr error
+
+	load := func(endpoint string, target interface{}) {
+		defer wg.Done()
+		err := source.Load(ctx, endpoint, target)
+		if err != nil {
+			failOnce.Do(func() {
+				firstErr = &EndpointError{Endpoint: endpoint, Source: fmt.Sprint(source), Err: err}
+				cancel()
+			})
 		}
-
-		// Wait before retrying
-		time.Sleep(2 * time.Second)
 	}
 
-	return nil, fmt.Errorf("failed to geocode location %s after 3 attempts: %v", formattedLocation, err)
+	wg.Add(4)
+	go load(EndpointArtists, &dataset.Artists)
+	go load(EndpointLocations, &dataset.Locations)
+	go load(EndpointDates, &dataset.Dates)
+	go load(EndpointRelation, &dataset.Relations)
+	wg.Wait()
+
+	if firstErr != nil {
+		return models.Dataset{}, firstErr
+	}
+	return dataset, nil
 }
@@ -1,9 +1,13 @@
 package fetch
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"groupie-tracker-search-bar/internal/models"
 )
@@ -21,7 +25,7 @@ func TestFetchData(t *testing.T) {
 	defer server.Close()
 
 	var artists []models.Artist
-	err := FetchData(server.URL, &artists)
+	err := FetchData(context.Background(), server.URL, &artists)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -32,8 +36,150 @@ func TestFetchData(t *testing.T) {
 }
 
 func TestFetchAllData(t *testing.T) {
-	_, _, _, _, err := FetchAllData()
+	dataset, err := FetchAllData(context.Background(), EmbeddedSource())
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
+
+	if len(dataset.Artists) == 0 {
+		t.Fatalf("expected artists, got none")
+	}
+	if len(dataset.Locations.Index) != len(dataset.Artists) || len(dataset.Dates.Index) != len(dataset.Artists) || len(dataset.Relations.Index) != len(dataset.Artists) {
+		t.Errorf("expected one location, date and relation entry per artist, got %d/%d/%d for %d artists",
+			len(dataset.Locations.Index), len(dataset.Dates.Index), len(dataset.Relations.Index), len(dataset.Artists))
+	}
+}
+
+func TestFetchAllDataHTTPSource(t *testing.T) {
+	responses := map[string]string{
+		"/api/artists":   `[{"id": 1, "name": "Artist 1"}]`,
+		"/api/locations": `{"index": [{"id": 1, "locations": ["london-uk"]}]}`,
+		"/api/dates":     `{"index": [{"id": 1, "dates": ["*01-01-2020"]}]}`,
+		"/api/relation":  `{"index": [{"id": 1, "datesLocations": {"london-uk": ["01-01-2020"]}}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	dataset, err := FetchAllData(context.Background(), HTTPSource{BaseURL: server.URL + "/api"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(dataset.Artists) != 1 || dataset.Artists[0].Name != "Artist 1" {
+		t.Errorf("expected one artist named 'Artist 1', got %v", dataset.Artists)
+	}
+	if got := dataset.Relations.Index[0].DatesLocations["london-uk"]; len(got) != 1 {
+		t.Errorf("expected one date for london-uk, got %v", got)
+	}
+}
+
+func TestNewDataSource(t *testing.T) {
+	if _, err := NewDataSource(SourceConfig{Kind: "dir"}); err == nil {
+		t.Errorf("expected an error for a dir source without a directory")
+	}
+	if _, err := NewDataSource(SourceConfig{Kind: "ftp"}); err == nil {
+		t.Errorf("expected an error for an unknown source kind")
+	}
+
+	source, err := NewDataSource(SourceConfig{Kind: "http"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	httpSource := source.(HTTPSource)
+	if httpSource.BaseURL != DefaultBaseURL || httpSource.Timeout != DefaultTimeout {
+		t.Errorf("expected defaults %q and %v, got %q and %v", DefaultBaseURL, DefaultTimeout, httpSource.BaseURL, httpSource.Timeout)
+	}
+}
+
+func TestFetchAllDataNamesFailingEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dates":
+			w.Write([]byte(`not json`))
+		default:
+			// Every other endpoint hangs until the failing one cancels it.
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	_, err := FetchAllData(context.Background(), HTTPSource{BaseURL: server.URL})
+
+	var endpointErr *EndpointError
+	if !errors.As(err, &endpointErr) {
+		t.Fatalf("expected an *EndpointError, got %v", err)
+	}
+	if endpointErr.Endpoint != EndpointDates {
+		t.Errorf("expected endpoint %q to be reported, got %q", EndpointDates, endpointErr.Endpoint)
+	}
+}
+
+func TestFetchAllDataTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := FetchAllData(context.Background(), HTTPSource{BaseURL: server.URL, Timeout: 50 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the requests to be abandoned after the timeout, took %v", elapsed)
+	}
+}
+
+func TestFetchDataValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/unavailable":
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("<html>Application Error</html>"))
+		case "/html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html></html>"))
+		case "/unknown-field":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"id": 1, "name": "Artist 1", "label": "EMI"}]`))
+		case "/large":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"id": 1, "name": "` + strings.Repeat("a", 100) + `"}]`))
+		}
+	}))
+	defer server.Close()
+
+	var artists []models.Artist
+
+	err := FetchData(context.Background(), server.URL+"/unavailable", &artists)
+	var statusErr *UpstreamStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected an *UpstreamStatusError with status 503, got %v", err)
+	}
+
+	var decodeErr *DecodeError
+	if err := FetchData(context.Background(), server.URL+"/html", &artists); !errors.As(err, &decodeErr) {
+		t.Errorf("expected a *DecodeError for an HTML body, got %v", err)
+	}
+
+	if err := FetchData(context.Background(), server.URL+"/unknown-field", &artists); err != nil {
+		t.Errorf("expected unknown fields to be ignored by default, got %v", err)
+	}
+	err = FetchDataWithOptions(context.Background(), server.URL+"/unknown-field", &artists, Options{Strict: true})
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("expected a *DecodeError for an unknown field in strict mode, got %v", err)
+	}
+
+	err = FetchDataWithOptions(context.Background(), server.URL+"/large", &artists, Options{MaxBodySize: 64})
+	if !errors.As(err, &decodeErr) {
+		t.Errorf("expected a *DecodeError for an oversized body, got %v", err)
+	}
 }
@@ -0,0 +1,128 @@
+package fetch
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+// Endpoint names understood by every DataSource. They match the paths of the
+// upstream API, so an HTTP source can append them to its base URL and a
+// directory source can read them as <endpoint>.json.
+const (
+	EndpointArtists   = "artists"
+	EndpointLocations = "locations"
+	EndpointDates     = "dates"
+	EndpointRelation  = "relation"
+)
+
+// DefaultBaseURL is the public groupietrackers API.
+const DefaultBaseURL = "https://groupietrackers.herokuapp.com/api"
+
+// DefaultTimeout bounds each upstream request when no timeout is configured.
+const DefaultTimeout = 30 * time.Second
+
+// DataSource loads one endpoint of the artist dataset into target.
+// Implementations must stop early when ctx is cancelled.
+type DataSource interface {
+	Load(ctx context.Context, endpoint string, target interface{}) error
+}
+
+// HTTPSource reads the dataset from an upstream API such as DefaultBaseURL or a mirror of it.
+type HTTPSource struct {
+	BaseURL string
+	Timeout time.Duration // per-request deadline; zero means no deadline beyond ctx
+	Options Options
+}
+
+func (s HTTPSource) Load(ctx context.Context, endpoint string, target interface{}) error {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+	return FetchDataWithOptions(ctx, strings.TrimRight(s.BaseURL, "/")+"/"+endpoint, target, s.Options)
+}
+
+func (s HTTPSource) String() string {
+	return s.BaseURL
+}
+
+// FSSource reads the dataset from <endpoint>.json files in a file system,
+// using the same JSON layout as the upstream API.
+type FSSource struct {
+	FS   fs.FS
+	Name string
+}
+
+// NewDirSource returns a source reading JSON files from a local directory.
+func NewDirSource(dir string) FSSource {
+	return FSSource{FS: os.DirFS(dir), Name: "dir:" + dir}
+}
+
+//go:embed snapshot/*.json
+var snapshotFiles embed.FS
+
+// EmbeddedSource returns the small sample dataset compiled into the binary,
+// useful for offline development and tests.
+func EmbeddedSource() FSSource {
+	sub, err := fs.Sub(snapshotFiles, "snapshot")
+	if err != nil {
+		panic(err) // the embed pattern guarantees the directory exists
+	}
+	return FSSource{FS: sub, Name: "embedded"}
+}
+
+func (s FSSource) Load(ctx context.Context, endpoint string, target interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	name := endpoint + ".json"
+	file, err := s.FS.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return decodeJSON(name, file, target, Options{})
+}
+
+func (s FSSource) String() string {
+	return s.Name
+}
+
+// SourceConfig selects the data source at startup.
+type SourceConfig struct {
+	Kind     string        // "http", "dir" or "embedded"
+	Location string        // base URL for "http" (empty means DefaultBaseURL), directory for "dir"
+	Timeout  time.Duration // per-request deadline for "http" (zero means DefaultTimeout)
+	Strict   bool          // reject unknown JSON fields from the "http" source
+}
+
+// NewDataSource builds the data source described by cfg.
+func NewDataSource(cfg SourceConfig) (DataSource, error) {
+	switch cfg.Kind {
+	case "http", "":
+		source := HTTPSource{BaseURL: cfg.Location, Timeout: cfg.Timeout, Options: Options{Strict: cfg.Strict}}
+		if source.BaseURL == "" {
+			source.BaseURL = DefaultBaseURL
+		}
+		if source.Timeout == 0 {
+			source.Timeout = DefaultTimeout
+		}
+		return source, nil
+	case "dir":
+		if cfg.Location == "" {
+			return nil, fmt.Errorf("data source %q needs a directory", cfg.Kind)
+		}
+		return NewDirSource(cfg.Location), nil
+	case "embedded":
+		return EmbeddedSource(), nil
+	default:
+		return nil, fmt.Errorf("unknown data source %q", cfg.Kind)
+	}
+}
@@ -0,0 +1,66 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// Metrics counts cache lookups, published with expvar as "geocode_cache":
+// hits, misses (the wrapped geocoder was asked), negative_hits (a place known
+// to be unresolvable was asked again) and errors (the wrapped geocoder failed
+// for a reason other than ErrNotFound while the caller was still waiting).
+var Metrics = expvar.NewMap("geocode_cache")
+
+// countError counts a failed lookup in Metrics, unless the place is merely
+// unknown or the caller gave up on it, as a visitor leaving a page does
+func countError(ctx context.Context, err error) {
+	if errors.Is(err, ErrNotFound) || ctx.Err() != nil {
+		return
+	}
+	Metrics.Add("errors", 1)
+}
+
+// MemoryCache remembers the coordinates its Geocoder found, so each place is
+// looked up once per process. Failures are not remembered.
+type MemoryCache struct {
+	Geocoder Geocoder
+
+	mu     sync.RWMutex
+	coords map[string]Coordinates
+}
+
+// NewMemoryCache caches the results of g.
+func NewMemoryCache(g Geocoder) *MemoryCache {
+	return &MemoryCache{Geocoder: g, coords: map[string]Coordinates{}}
+}
+
+func (c *MemoryCache) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	key := place.Slug()
+	c.mu.RLock()
+	coords, ok := c.coords[key]
+	c.mu.RUnlock()
+	if ok {
+		Metrics.Add("hits", 1)
+		return coords, nil
+	}
+
+	Metrics.Add("misses", 1)
+	coords, err := c.Geocoder.Geocode(ctx, place)
+	if err != nil {
+		countError(ctx, err)
+		return Coordinates{}, err
+	}
+	c.mu.Lock()
+	c.coords[key] = coords
+	c.mu.Unlock()
+	return coords, nil
+}
+
+func (c *MemoryCache) String() string {
+	return fmt.Sprintf("%v (cached in memory)", c.Geocoder)
+}
@@ -0,0 +1,208 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// FileCacheVersion is the cache file format this build writes.
+const FileCacheVersion = 1
+
+const (
+	// DefaultTTL is how long found coordinates are kept when no TTL is configured.
+	DefaultTTL = 30 * 24 * time.Hour
+	// DefaultNegativeTTL is how long a place is remembered as unresolvable when no TTL is configured.
+	DefaultNegativeTTL = 24 * time.Hour
+	// DefaultSaveDelay is how long new entries wait before the cache file is written.
+	DefaultSaveDelay = 5 * time.Second
+)
+
+// FileCache remembers the results of its Geocoder in a JSON file, so they
+// survive restarts. Places the geocoder could not find are remembered too, for
+// a shorter time, so they are not looked up on every page view; other errors,
+// such as a timeout, are not remembered.
+//
+// New entries are written SaveDelay after the first of them, so a burst of
+// lookups rewrites the file once rather than once per place; Flush writes them
+// straight away.
+type FileCache struct {
+	Geocoder    Geocoder
+	Path        string
+	TTL         time.Duration // how long coordinates are kept
+	NegativeTTL time.Duration // how long a place is remembered as not found
+	SaveDelay   time.Duration // how long new entries wait to be written
+
+	now     func() time.Time
+	mu      sync.Mutex // guards entries, dirty and saving
+	saveMu  sync.Mutex // serializes writes to the file
+	entries map[string]cacheEntry
+	dirty   bool        // entries holds lookups the file does not
+	saving  *time.Timer // pending write of the dirty entries
+}
+
+// cacheEntry is a cached lookup; Coordinates is nil for a place that was not found
+type cacheEntry struct {
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
+	Expires     time.Time    `json:"expires"`
+}
+
+type cacheFile struct {
+	Version int                   `json:"version"`
+	Entries map[string]cacheEntry `json:"entries"`
+}
+
+// OpenFileCache caches the results of g in the file at path, loading the
+// entries it already holds. A missing file is an empty cache. Zero TTLs mean
+// DefaultTTL and DefaultNegativeTTL; the cache saves after DefaultSaveDelay.
+func OpenFileCache(path string, g Geocoder, ttl, negativeTTL time.Duration) (*FileCache, error) {
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	if negativeTTL == 0 {
+		negativeTTL = DefaultNegativeTTL
+	}
+	c := &FileCache{Geocoder: g, Path: path, TTL: ttl, NegativeTTL: negativeTTL, SaveDelay: DefaultSaveDelay, now: time.Now, entries: map[string]cacheEntry{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decoding geocode cache %s: %w", path, err)
+	}
+	if file.Version < 1 || file.Version > FileCacheVersion {
+		return nil, fmt.Errorf("geocode cache %s has unsupported version %d (this build reads up to %d)", path, file.Version, FileCacheVersion)
+	}
+	for key, entry := range file.Entries {
+		c.entries[key] = entry
+	}
+	return c, nil
+}
+
+func (c *FileCache) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	key := place.Slug()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.Expires) {
+		if entry.Coordinates == nil {
+			Metrics.Add("negative_hits", 1)
+			return Coordinates{}, fmt.Errorf("%s: %w", place, ErrNotFound)
+		}
+		Metrics.Add("hits", 1)
+		return *entry.Coordinates, nil
+	}
+
+	Metrics.Add("misses", 1)
+	coords, err := c.Geocoder.Geocode(ctx, place)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.store(key, cacheEntry{Expires: c.now().Add(c.NegativeTTL)})
+		return Coordinates{}, err
+	case err != nil:
+		countError(ctx, err)
+		return Coordinates{}, err
+	}
+	c.store(key, cacheEntry{Coordinates: &coords, Expires: c.now().Add(c.TTL)})
+	return coords, nil
+}
+
+// store records an entry, scheduling a save unless one is already pending
+func (c *FileCache) store(key string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	c.dirty = true
+	if c.saving == nil {
+		c.saving = time.AfterFunc(c.SaveDelay, c.saveLater)
+	}
+}
+
+// saveLater writes the entries stored since the last save. A cache that cannot
+// be saved keeps serving from memory, so the failure is only logged.
+func (c *FileCache) saveLater() {
+	if err := c.Flush(); err != nil {
+		log.Printf("Error saving geocode cache: %v", err)
+	}
+}
+
+// Flush saves the entries not yet written to the file, if any, without
+// waiting for SaveDelay.
+func (c *FileCache) Flush() error {
+	c.mu.Lock()
+	if c.saving != nil {
+		c.saving.Stop()
+		c.saving = nil
+	}
+	dirty := c.dirty
+	c.mu.Unlock()
+	if !dirty {
+		return nil
+	}
+	return c.Save()
+}
+
+// Save writes the unexpired entries to the cache file, replacing it atomically.
+func (c *FileCache) Save() error {
+	// Saves run one at a time, so an older copy never replaces a newer one
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+
+	c.mu.Lock()
+	file := cacheFile{Version: FileCacheVersion, Entries: map[string]cacheEntry{}}
+	now := c.now()
+	for key, entry := range c.entries {
+		if now.Before(entry.Expires) {
+			file.Entries[key] = entry
+		}
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	c.dirty = false
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := c.write(data); err != nil {
+		// Keep the entries marked unsaved, so the next save tries again
+		c.mu.Lock()
+		c.dirty = true
+		c.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// write replaces the cache file with data atomically
+func (c *FileCache) write(data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(c.Path), filepath.Base(c.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.Path)
+}
+
+func (c *FileCache) String() string {
+	return fmt.Sprintf("%v (cached in %s)", c.Geocoder, c.Path)
+}
@@ -0,0 +1,186 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// failingGeocoder fails every lookup with err
+type failingGeocoder struct{ err error }
+
+func (g failingGeocoder) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	return Coordinates{}, g.err
+}
+
+func metric(name string) int64 {
+	if v, ok := Metrics.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestFileCachePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geocode.json")
+	inner := &countingGeocoder{table: Static{"nairobi-kenya": nairobi}}
+	nairobiPlace := models.ParsePlace("nairobi-kenya")
+
+	cache, err := OpenFileCache(path, inner, 0, 0)
+	if err != nil {
+		t.Fatalf("expected a missing file to open as an empty cache, got %v", err)
+	}
+	if coords, err := cache.Geocode(context.Background(), nairobiPlace); err != nil || coords != nairobi {
+		t.Fatalf("expected %v, got %v (%v)", nairobi, coords, err)
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("expected no error saving the cache, got %v", err)
+	}
+
+	// A new cache on the same file answers without asking the geocoder
+	reopened, err := OpenFileCache(path, failingGeocoder{errors.New("offline")}, 0, 0)
+	if err != nil {
+		t.Fatalf("expected no error reopening the cache, got %v", err)
+	}
+	hits := metric("hits")
+	if coords, err := reopened.Geocode(context.Background(), nairobiPlace); err != nil || coords != nairobi {
+		t.Errorf("expected %v from the file, got %v (%v)", nairobi, coords, err)
+	}
+	if metric("hits") != hits+1 {
+		t.Errorf("expected the lookup to count as a hit")
+	}
+	if n := inner.calls.Load(); n != 1 {
+		t.Errorf("expected 1 lookup, got %d", n)
+	}
+}
+
+func TestFileCacheTTL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geocode.json")
+	inner := &countingGeocoder{table: Static{"nairobi-kenya": nairobi}}
+	cache, _ := OpenFileCache(path, inner, time.Hour, time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	cache.Geocode(context.Background(), models.ParsePlace("nairobi-kenya"))
+	cache.Geocode(context.Background(), models.ParsePlace("atlantis-nowhere"))
+
+	// Both are cached, the unknown place as a negative entry
+	negativeHits := metric("negative_hits")
+	cache.Geocode(context.Background(), models.ParsePlace("nairobi-kenya"))
+	if _, err := cache.Geocode(context.Background(), models.ParsePlace("atlantis-nowhere")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound from the negative entry, got %v", err)
+	}
+	if n := inner.calls.Load(); n != 2 {
+		t.Errorf("expected 2 lookups, got %d", n)
+	}
+	if metric("negative_hits") != negativeHits+1 {
+		t.Errorf("expected a negative hit to be counted")
+	}
+
+	// The negative entry expires first
+	now = now.Add(2 * time.Minute)
+	cache.Geocode(context.Background(), models.ParsePlace("nairobi-kenya"))
+	cache.Geocode(context.Background(), models.ParsePlace("atlantis-nowhere"))
+	if n := inner.calls.Load(); n != 3 {
+		t.Errorf("expected only the negative entry to be looked up again, got %d lookups", n)
+	}
+
+	now = now.Add(time.Hour)
+	cache.Geocode(context.Background(), models.ParsePlace("nairobi-kenya"))
+	if n := inner.calls.Load(); n != 4 {
+		t.Errorf("expected the expired coordinates to be looked up again, got %d lookups", n)
+	}
+}
+
+func TestFileCacheSkipsErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geocode.json")
+	cache, _ := OpenFileCache(path, failingGeocoder{errors.New("timeout")}, 0, 0)
+
+	errs := metric("errors")
+	if _, err := cache.Geocode(context.Background(), models.ParsePlace("nairobi-kenya")); err == nil {
+		t.Fatalf("expected the geocoder error")
+	}
+	if metric("errors") != errs+1 {
+		t.Errorf("expected the error to be counted")
+	}
+
+	cache.Geocoder = Static{"nairobi-kenya": nairobi}
+	if coords, err := cache.Geocode(context.Background(), models.ParsePlace("nairobi-kenya")); err != nil || coords != nairobi {
+		t.Errorf("expected a failed lookup not to be cached, got %v (%v)", coords, err)
+	}
+}
+
+func TestOpenFileCacheVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geocode.json")
+	os.WriteFile(path, []byte(`{"version": 99, "entries": {}}`), 0o644)
+	if _, err := OpenFileCache(path, Static{}, 0, 0); err == nil {
+		t.Errorf("expected an error for an unsupported version")
+	}
+}
+
+func TestWarm(t *testing.T) {
+	inner := &countingGeocoder{table: Static{"nairobi-kenya": nairobi}}
+	cache := NewMemoryCache(inner)
+	places := []models.Place{models.ParsePlace("nairobi-kenya"), models.ParsePlace("atlantis-nowhere")}
+
+	failed, err := Warm(context.Background(), cache, places)
+	if err != nil || len(failed) != 1 || failed[places[1]] == nil {
+		t.Errorf("expected only %v to fail, got %v (%v)", places[1], failed, err)
+	}
+	cache.Geocode(context.Background(), places[0])
+	if n := inner.calls.Load(); n != 2 {
+		t.Errorf("expected the warmed place to be served from the cache, got %d lookups", n)
+	}
+}
+
+func TestFileCacheSavesOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geocode.json")
+	table := Static{"nairobi-kenya": nairobi, "london-uk": {Lat: 51.5074, Lng: -0.1278}}
+	cache, _ := OpenFileCache(path, table, 0, 0)
+	cache.SaveDelay = 20 * time.Millisecond
+
+	// A burst of lookups is not written one by one, but together after the delay
+	for slug := range table {
+		cache.Geocode(context.Background(), models.ParsePlace(slug))
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no file before the delay, got %v", err)
+	}
+	waitFor(t, func() bool {
+		reopened, err := OpenFileCache(path, Static{}, 0, 0)
+		return err == nil && len(reopened.entries) == 2
+	})
+
+	// Warming saves once at the end, without waiting for the delay
+	warmPath := filepath.Join(t.TempDir(), "warm.json")
+	warmed, _ := OpenFileCache(warmPath, table, 0, 0)
+	places := []models.Place{models.ParsePlace("nairobi-kenya"), models.ParsePlace("london-uk")}
+	if _, err := Warm(context.Background(), warmed, places); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reopened, err := OpenFileCache(warmPath, Static{}, 0, 0); err != nil || len(reopened.entries) != 2 {
+		t.Errorf("expected the warmed entries in the file, got %v", err)
+	}
+}
+
+func TestCacheErrorsSkipCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	caches := map[string]Geocoder{"memory": NewMemoryCache(failingGeocoder{context.Canceled})}
+	caches["file"], _ = OpenFileCache(filepath.Join(t.TempDir(), "geocode.json"), failingGeocoder{context.Canceled}, 0, 0)
+
+	for name, cache := range caches {
+		errs := metric("errors")
+		if _, err := cache.Geocode(ctx, models.ParsePlace("nairobi-kenya")); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", name, err)
+		}
+		if n := metric("errors") - errs; n != 0 {
+			t.Errorf("%s: expected a lookup the caller gave up on not to count as an error, got %d", name, n)
+		}
+	}
+}
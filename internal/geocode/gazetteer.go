@@ -0,0 +1,50 @@
+package geocode
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+//go:embed gazetteer.json
+var gazetteerJSON []byte
+
+// Gazetteer returns the built-in table of coordinates, keyed by location slug.
+// It covers the concert locations the upstream API is known to use, so most
+// artist maps need no geocoding service at all.
+func Gazetteer() Static {
+	var table Static
+	if err := json.Unmarshal(gazetteerJSON, &table); err != nil {
+		panic(fmt.Sprintf("geocode: invalid built-in gazetteer: %v", err))
+	}
+	return table
+}
+
+// Chain asks each of its geocoders in turn, moving on only when a place is
+// not found, so a local table can answer before a network service is asked.
+type Chain []Geocoder
+
+func (c Chain) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	err := fmt.Errorf("%s: %w", place, ErrNotFound)
+	for _, g := range c {
+		var coords Coordinates
+		coords, err = g.Geocode(ctx, place)
+		if !errors.Is(err, ErrNotFound) {
+			return coords, err
+		}
+	}
+	return Coordinates{}, err
+}
+
+func (c Chain) String() string {
+	names := make([]string, len(c))
+	for i, g := range c {
+		names[i] = fmt.Sprint(g)
+	}
+	return strings.Join(names, ", then ")
+}
@@ -0,0 +1,157 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"groupie-tracker-search-bar/internal/fetch"
+	"groupie-tracker-search-bar/internal/models"
+	"groupie-tracker-search-bar/internal/snapshot"
+)
+
+// upstreamSnapshot points TestGazetteerCoversUpstream at a -export snapshot
+// of the live data; with -update the fixture is rewritten from it
+var (
+	upstreamSnapshot = flag.String("upstream-snapshot", "", "check the gazetteer against this -export snapshot")
+	update           = flag.Bool("update", false, "rewrite testdata/upstream_locations.txt from -upstream-snapshot")
+)
+
+const upstreamFixture = "testdata/upstream_locations.txt"
+
+// datasetSlugs lists every location slug of a dataset, from both the
+// locations and the relations endpoints, sorted and without duplicates
+func datasetSlugs(dataset models.Dataset) []string {
+	seen := make(map[string]bool)
+	for _, location := range dataset.Locations.Index {
+		for _, slug := range location.Locations {
+			seen[slug] = true
+		}
+	}
+	for _, relation := range dataset.Relations.Index {
+		for slug := range relation.DatesLocations {
+			seen[slug] = true
+		}
+	}
+	slugs := make([]string, 0, len(seen))
+	for slug := range seen {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	return slugs
+}
+
+func checkGazetteer(t *testing.T, slugs []string) {
+	t.Helper()
+	table := Gazetteer()
+	for _, slug := range slugs {
+		if _, err := table.Geocode(context.Background(), models.ParsePlace(slug)); err != nil {
+			t.Errorf("gazetteer has no coordinates for %q", slug)
+		}
+	}
+}
+
+// TestGazetteerCoversEmbeddedData checks that every location of the embedded
+// sample, which mirrors upstream slugs, resolves without a network geocoder
+func TestGazetteerCoversEmbeddedData(t *testing.T) {
+	dataset, err := fetch.FetchAllData(context.Background(), fetch.EmbeddedSource())
+	if err != nil {
+		t.Fatalf("loading embedded data: %v", err)
+	}
+	checkGazetteer(t, datasetSlugs(dataset))
+}
+
+// TestGazetteerCoversUpstream checks the committed list of upstream location
+// slugs. To refresh it from the live API:
+//
+//	go run . -export upstream.json
+//	go test ./internal/geocode -run Upstream -upstream-snapshot "$PWD/upstream.json" -update
+func TestGazetteerCoversUpstream(t *testing.T) {
+	if *upstreamSnapshot != "" {
+		snap, err := snapshot.Read(*upstreamSnapshot)
+		if err != nil {
+			t.Fatalf("reading snapshot: %v", err)
+		}
+		slugs := datasetSlugs(snap.Dataset())
+		if *update {
+			if err := writeFixture(slugs); err != nil {
+				t.Fatalf("updating %s: %v", upstreamFixture, err)
+			}
+		}
+		checkGazetteer(t, slugs)
+		return
+	}
+
+	slugs, err := readFixture()
+	if err != nil {
+		t.Fatalf("reading %s: %v", upstreamFixture, err)
+	}
+	if len(slugs) == 0 {
+		t.Fatalf("%s lists no locations", upstreamFixture)
+	}
+	checkGazetteer(t, slugs)
+}
+
+func readFixture() ([]string, error) {
+	data, err := os.ReadFile(upstreamFixture)
+	if err != nil {
+		return nil, err
+	}
+	var slugs []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			slugs = append(slugs, line)
+		}
+	}
+	return slugs, nil
+}
+
+func writeFixture(slugs []string) error {
+	var b strings.Builder
+	b.WriteString("# Location slugs of the upstream API (/api/locations), one per line.\n")
+	b.WriteString("# Refresh from a -export snapshot of the live data, see TestGazetteerCoversUpstream.\n")
+	for _, slug := range slugs {
+		b.WriteString(slug + "\n")
+	}
+	return os.WriteFile(upstreamFixture, []byte(b.String()), 0o644)
+}
+
+func TestGazetteerCoordinates(t *testing.T) {
+	for slug, c := range Gazetteer() {
+		if c.Lat < -90 || c.Lat > 90 || c.Lng < -180 || c.Lng > 180 || c == (Coordinates{}) {
+			t.Errorf("%s: invalid coordinates %v", slug, c)
+		}
+		if got := models.ParsePlace(slug).Slug(); got != slug {
+			t.Errorf("%s: does not round-trip through a Place (got %q)", slug, got)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	network := &countingGeocoder{table: Static{"nairobi-kenya": nairobi}}
+	chain := Chain{Static{"london-uk": {Lat: 51.5074, Lng: -0.1278}}, network}
+
+	if _, err := chain.Geocode(context.Background(), models.ParsePlace("london-uk")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n := network.calls.Load(); n != 0 {
+		t.Errorf("expected the local table to answer first, got %d network lookups", n)
+	}
+	if coords, err := chain.Geocode(context.Background(), models.ParsePlace("nairobi-kenya")); err != nil || coords != nairobi {
+		t.Errorf("expected %v from the next geocoder, got %v (%v)", nairobi, coords, err)
+	}
+	if _, err := chain.Geocode(context.Background(), models.ParsePlace("atlantis-nowhere")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	// Other errors stop the chain
+	failing := Chain{failingGeocoder{errors.New("offline")}, network}
+	if _, err := failing.Geocode(context.Background(), models.ParsePlace("nairobi-kenya")); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the first geocoder's error, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Package geocode turns concert places into map coordinates.
+package geocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// Coordinates is a point on Earth, in degrees.
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// LngLat returns the coordinates as [longitude, latitude], the order map libraries such as Mapbox GL expect.
+func (c Coordinates) LngLat() []float64 {
+	return []float64{c.Lng, c.Lat}
+}
+
+// Geocoder finds the coordinates of a place.
+type Geocoder interface {
+	Geocode(ctx context.Context, place models.Place) (Coordinates, error)
+}
+
+// ErrNotFound is returned by a Geocoder that has no coordinates for a place.
+var ErrNotFound = errors.New("place not found")
+
+// DefaultTimeout bounds each request to a geocoding service when no timeout is configured.
+const DefaultTimeout = 10 * time.Second
+
+// withTimeout applies a per-request deadline to ctx; zero means no deadline beyond ctx
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Config selects the geocoder at startup.
+type Config struct {
+	Provider string        // "mapbox", "nominatim", "static" or "gazetteer" (the built-in table alone)
+	URL      string        // base URL for "mapbox" and "nominatim" (empty means the public service)
+	Token    string        // access token for "mapbox"
+	Table    string        // JSON file of coordinates for "static"
+	Timeout  time.Duration // per-request deadline for "mapbox" and "nominatim" (zero means DefaultTimeout)
+}
+
+// New builds the geocoder described by cfg.
+func New(cfg Config) (Geocoder, error) {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	switch cfg.Provider {
+	case "mapbox", "":
+		g := Mapbox{BaseURL: cfg.URL, Token: cfg.Token, Timeout: timeout}
+		if g.BaseURL == "" {
+			g.BaseURL = DefaultMapboxURL
+		}
+		if g.Token == "" {
+			return nil, fmt.Errorf("geocoder %q needs an access token", "mapbox")
+		}
+		return g, nil
+	case "nominatim":
+		g := Nominatim{BaseURL: cfg.URL, Timeout: timeout}
+		if g.BaseURL == "" {
+			g.BaseURL = DefaultNominatimURL
+		}
+		return g, nil
+	case "static":
+		if cfg.Table == "" {
+			return nil, fmt.Errorf("geocoder %q needs a table file", cfg.Provider)
+		}
+		return LoadStatic(cfg.Table)
+	case "gazetteer":
+		return Gazetteer(), nil
+	default:
+		return nil, fmt.Errorf("unknown geocoder %q", cfg.Provider)
+	}
+}
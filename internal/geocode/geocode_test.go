@@ -0,0 +1,159 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+var nairobi = Coordinates{Lat: -1.2921, Lng: 36.8219}
+
+// TestMapbox geocodes against a local server answering like the Mapbox API
+func TestMapbox(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.Contains(r.URL.Path, "Nairobi, Kenya") {
+			w.Write([]byte(`{"features": []}`))
+			return
+		}
+		w.Write([]byte(`{"features": [{"center": [36.8219, -1.2921]}]}`))
+	}))
+	defer mockServer.Close()
+
+	g := Mapbox{BaseURL: mockServer.URL, Token: "test-token"}
+	coords, err := g.Geocode(context.Background(), models.ParsePlace("nairobi-kenya"))
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	if coords != nairobi {
+		t.Errorf("expected %v, but got %v", nairobi, coords)
+	}
+
+	_, err = g.Geocode(context.Background(), models.ParsePlace("atlantis-nowhere"))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	g.Token = "wrong"
+	if _, err := g.Geocode(context.Background(), models.ParsePlace("nairobi-kenya")); err == nil {
+		t.Errorf("expected an error for a rejected token")
+	}
+}
+
+// TestNominatim geocodes against a local server answering like Nominatim
+func TestNominatim(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search" || r.Header.Get("User-Agent") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("q") != "Nairobi, Kenya" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"lat": "-1.2921", "lon": "36.8219", "display_name": "Nairobi"}]`))
+	}))
+	defer mockServer.Close()
+
+	g := Nominatim{BaseURL: mockServer.URL}
+	coords, err := g.Geocode(context.Background(), models.ParsePlace("nairobi-kenya"))
+	if err != nil {
+		t.Fatalf("expected no error, but got %v", err)
+	}
+	if coords != nairobi {
+		t.Errorf("expected %v, but got %v", nairobi, coords)
+	}
+
+	_, err = g.Geocode(context.Background(), models.ParsePlace("atlantis-nowhere"))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStatic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "table.json")
+	os.WriteFile(path, []byte(`{"nairobi-kenya": {"lat": -1.2921, "lng": 36.8219}}`), 0o644)
+
+	g, err := LoadStatic(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if coords, err := g.Geocode(context.Background(), models.ParsePlace("nairobi-kenya")); err != nil || coords != nairobi {
+		t.Errorf("expected %v, got %v (%v)", nairobi, coords, err)
+	}
+	if _, err := g.Geocode(context.Background(), models.ParsePlace("atlantis-nowhere")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// countingGeocoder answers from a table and counts its lookups
+type countingGeocoder struct {
+	table Static
+	calls atomic.Int32
+}
+
+func (g *countingGeocoder) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	g.calls.Add(1)
+	return g.table.Geocode(ctx, place)
+}
+
+func TestMemoryCache(t *testing.T) {
+	inner := &countingGeocoder{table: Static{"nairobi-kenya": nairobi}}
+	cache := NewMemoryCache(inner)
+
+	for i := 0; i < 3; i++ {
+		if coords, err := cache.Geocode(context.Background(), models.ParsePlace("nairobi-kenya")); err != nil || coords != nairobi {
+			t.Fatalf("expected %v, got %v (%v)", nairobi, coords, err)
+		}
+	}
+	if n := inner.calls.Load(); n != 1 {
+		t.Errorf("expected 1 lookup, got %d", n)
+	}
+
+	// Failures are retried on the next request; an unknown place is not an error
+	errs := metric("errors")
+	cache.Geocode(context.Background(), models.ParsePlace("atlantis-nowhere"))
+	cache.Geocode(context.Background(), models.ParsePlace("atlantis-nowhere"))
+	if n := inner.calls.Load(); n != 3 {
+		t.Errorf("expected failed lookups to be retried, got %d lookups", n)
+	}
+	if n := metric("errors") - errs; n != 0 {
+		t.Errorf("expected unknown places not to count as errors, got %d", n)
+	}
+}
+
+func TestNew(t *testing.T) {
+	g, err := New(Config{Provider: "mapbox", Token: "t"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if m, ok := g.(Mapbox); !ok || m.BaseURL != DefaultMapboxURL || m.Timeout != DefaultTimeout {
+		t.Errorf("expected a Mapbox geocoder with the defaults, got %#v", g)
+	}
+	if g, err := New(Config{Provider: "nominatim", URL: "http://localhost:8081"}); err != nil || g.(Nominatim).BaseURL != "http://localhost:8081" {
+		t.Errorf("expected a Nominatim geocoder for the given URL, got %#v (%v)", g, err)
+	}
+
+	for _, cfg := range []Config{
+		{Provider: "mapbox"},
+		{Provider: "static"},
+		{Provider: "google"},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("New(%+v): expected an error", cfg)
+		}
+	}
+}
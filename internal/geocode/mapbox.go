@@ -0,0 +1,48 @@
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"groupie-tracker-search-bar/internal/fetch"
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// DefaultMapboxURL is the public Mapbox API.
+const DefaultMapboxURL = "https://api.mapbox.com"
+
+// Mapbox geocodes with the Mapbox Geocoding API, or a server that answers like it.
+type Mapbox struct {
+	BaseURL string
+	Token   string
+	Timeout time.Duration // per-request deadline; zero means no deadline beyond ctx
+}
+
+type mapboxResponse struct {
+	Features []struct {
+		Center []float64 `json:"center"` // longitude, latitude
+	} `json:"features"`
+}
+
+func (g Mapbox) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	ctx, cancel := withTimeout(ctx, g.Timeout)
+	defer cancel()
+
+	geocodeURL := fmt.Sprintf("%s/geocoding/v5/mapbox.places/%s.json?access_token=%s",
+		g.BaseURL, url.PathEscape(place.String()), url.QueryEscape(g.Token))
+	var response mapboxResponse
+	if err := fetch.FetchData(ctx, geocodeURL, &response); err != nil {
+		return Coordinates{}, err
+	}
+	if len(response.Features) == 0 || len(response.Features[0].Center) != 2 {
+		return Coordinates{}, fmt.Errorf("%s: %w", place, ErrNotFound)
+	}
+	center := response.Features[0].Center
+	return Coordinates{Lat: center[1], Lng: center[0]}, nil
+}
+
+func (g Mapbox) String() string {
+	return "mapbox " + g.BaseURL
+}
@@ -0,0 +1,62 @@
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"groupie-tracker-search-bar/internal/fetch"
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// DefaultNominatimURL is the public OpenStreetMap Nominatim service.
+const DefaultNominatimURL = "https://nominatim.openstreetmap.org"
+
+// nominatimUserAgent identifies the application, as the Nominatim usage policy requires.
+const nominatimUserAgent = "groupie-tracker/1.0"
+
+// Nominatim geocodes with a Nominatim-compatible search API.
+type Nominatim struct {
+	BaseURL string
+	Timeout time.Duration // per-request deadline; zero means no deadline beyond ctx
+}
+
+type nominatimResult struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+func (g Nominatim) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	ctx, cancel := withTimeout(ctx, g.Timeout)
+	defer cancel()
+
+	query := url.Values{}
+	query.Set("q", place.String())
+	query.Set("format", "json")
+	query.Set("limit", "1")
+	opts := fetch.Options{Header: http.Header{"User-Agent": {nominatimUserAgent}}}
+
+	var results []nominatimResult
+	if err := fetch.FetchDataWithOptions(ctx, g.BaseURL+"/search?"+query.Encode(), &results, opts); err != nil {
+		return Coordinates{}, err
+	}
+	if len(results) == 0 {
+		return Coordinates{}, fmt.Errorf("%s: %w", place, ErrNotFound)
+	}
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("%s: invalid latitude %q", place, results[0].Lat)
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("%s: invalid longitude %q", place, results[0].Lon)
+	}
+	return Coordinates{Lat: lat, Lng: lng}, nil
+}
+
+func (g Nominatim) String() string {
+	return "nominatim " + g.BaseURL
+}
@@ -0,0 +1,40 @@
+package geocode
+
+import (
+	"context"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// ResolveAll looks up every place with g concurrently and waits for all of
+// them. It returns the coordinates of the places found and the errors of the
+// others, so a caller can use what resolved instead of failing as a whole.
+// Every lookup has finished when it returns; ending ctx makes the pending ones
+// fail sooner.
+func ResolveAll(ctx context.Context, g Geocoder, places []models.Place) (resolved map[models.Place]Coordinates, failed map[models.Place]error) {
+	type result struct {
+		place  models.Place
+		coords Coordinates
+		err    error
+	}
+	// One slot per place, so no lookup blocks on sending its result
+	results := make(chan result, len(places))
+	for _, place := range places {
+		go func(place models.Place) {
+			coords, err := g.Geocode(ctx, place)
+			results <- result{place, coords, err}
+		}(place)
+	}
+
+	resolved = map[models.Place]Coordinates{}
+	failed = map[models.Place]error{}
+	for range places {
+		r := <-results
+		if r.err != nil {
+			failed[r.place] = r.err
+			continue
+		}
+		resolved[r.place] = r.coords
+	}
+	return resolved, failed
+}
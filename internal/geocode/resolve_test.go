@@ -0,0 +1,74 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// selectiveGeocoder answers from a table, failing the places listed in errs
+// with their error
+type selectiveGeocoder struct {
+	table Static
+	errs  map[string]error
+}
+
+func (g selectiveGeocoder) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	if err, ok := g.errs[place.Slug()]; ok {
+		return Coordinates{}, err
+	}
+	return g.table.Geocode(ctx, place)
+}
+
+func TestResolveAll(t *testing.T) {
+	london := Coordinates{Lat: 51.5074, Lng: -0.1278}
+	unavailable := errors.New("service unavailable")
+	g := selectiveGeocoder{
+		table: Static{"nairobi-kenya": nairobi, "london-uk": london},
+		errs:  map[string]error{"osaka-japan": unavailable},
+	}
+	places := []models.Place{
+		models.ParsePlace("nairobi-kenya"),
+		models.ParsePlace("osaka-japan"),
+		models.ParsePlace("london-uk"),
+		models.ParsePlace("atlantis-nowhere"),
+	}
+
+	before := runtime.NumGoroutine()
+	resolved, failed := ResolveAll(context.Background(), g, places)
+
+	if len(resolved) != 2 || resolved[places[0]] != nairobi || resolved[places[2]] != london {
+		t.Errorf("expected nairobi and london to resolve, got %v", resolved)
+	}
+	if len(failed) != 2 || !errors.Is(failed[places[1]], unavailable) || !errors.Is(failed[places[3]], ErrNotFound) {
+		t.Errorf("expected osaka and atlantis to fail with their errors, got %v", failed)
+	}
+	waitFor(t, func() bool { return runtime.NumGoroutine() <= before })
+}
+
+func TestResolveAllCancelled(t *testing.T) {
+	inner := &blockingGeocoder{release: make(chan struct{})}
+	places := []models.Place{models.ParsePlace("nairobi-kenya"), models.ParsePlace("osaka-japan")}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	before := runtime.NumGoroutine()
+	resolved, failed := ResolveAll(ctx, inner, places)
+
+	if len(resolved) != 0 || len(failed) != 2 {
+		t.Fatalf("expected every place to fail, got %v resolved and %v failed", resolved, failed)
+	}
+	for place, err := range failed {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%v: expected context.DeadlineExceeded, got %v", place, err)
+		}
+	}
+	if inner.running.Load() != 0 {
+		t.Errorf("expected no lookup still running")
+	}
+	waitFor(t, func() bool { return runtime.NumGoroutine() <= before })
+}
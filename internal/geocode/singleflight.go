@@ -0,0 +1,87 @@
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// SingleFlight coalesces concurrent lookups of the same place: while one is in
+// flight, later callers wait for its result instead of asking Geocoder again.
+// The shared lookup is cancelled only once every caller waiting for it has
+// given up, so one visitor leaving does not fail the page of another.
+type SingleFlight struct {
+	Geocoder Geocoder
+
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// flight is a lookup in progress and the callers waiting for it
+type flight struct {
+	done    chan struct{}
+	coords  Coordinates
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// NewSingleFlight coalesces concurrent lookups made through g.
+func NewSingleFlight(g Geocoder) *SingleFlight {
+	return &SingleFlight{Geocoder: g, calls: map[string]*flight{}}
+}
+
+func (s *SingleFlight) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	key := place.Slug()
+
+	s.mu.Lock()
+	f, ok := s.calls[key]
+	if !ok {
+		// The lookup outlives the caller that started it, as long as someone still waits for it
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		s.calls[key] = f
+		go s.run(flightCtx, key, place, f)
+	}
+	f.waiters++
+	s.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.coords, f.err
+	case <-ctx.Done():
+		s.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Nobody wants the result any more; later callers start a new lookup
+			f.cancel()
+			s.forget(key, f)
+		}
+		s.mu.Unlock()
+		return Coordinates{}, ctx.Err()
+	}
+}
+
+func (s *SingleFlight) run(ctx context.Context, key string, place models.Place, f *flight) {
+	defer f.cancel()
+	f.coords, f.err = s.Geocoder.Geocode(ctx, place)
+
+	s.mu.Lock()
+	s.forget(key, f)
+	s.mu.Unlock()
+	close(f.done)
+}
+
+// forget removes f from the lookups in flight unless a newer one replaced it.
+// s.mu must be held.
+func (s *SingleFlight) forget(key string, f *flight) {
+	if s.calls[key] == f {
+		delete(s.calls, key)
+	}
+}
+
+func (s *SingleFlight) String() string {
+	return fmt.Sprint(s.Geocoder)
+}
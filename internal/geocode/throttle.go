@@ -0,0 +1,99 @@
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// Throttle protects a geocoding service: at most a fixed number of lookups run
+// at once, like a pool of workers, and they start no faster than a fixed rate.
+// A caller whose context ends while it waits for its turn gives up without
+// making a request, and hands its turn back.
+type Throttle struct {
+	Geocoder Geocoder
+
+	slots    chan struct{} // one per lookup allowed to run; nil means no limit
+	interval time.Duration // minimum time between two lookups starting; zero means no limit
+
+	mu   sync.Mutex
+	next time.Time // when the next lookup may start
+}
+
+// NewThrottle limits g to concurrency lookups at a time and rate lookups per
+// second. Zero disables either limit.
+func NewThrottle(g Geocoder, concurrency int, rate float64) *Throttle {
+	t := &Throttle{Geocoder: g}
+	if concurrency > 0 {
+		t.slots = make(chan struct{}, concurrency)
+	}
+	if rate > 0 {
+		t.interval = time.Duration(float64(time.Second) / rate)
+	}
+	return t
+}
+
+func (t *Throttle) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+			defer func() { <-t.slots }()
+		case <-ctx.Done():
+			return Coordinates{}, ctx.Err()
+		}
+	}
+	if err := t.wait(ctx); err != nil {
+		return Coordinates{}, err
+	}
+	return t.Geocoder.Geocode(ctx, place)
+}
+
+// wait blocks until the rate limit lets another lookup start, or ctx is done
+func (t *Throttle) wait(ctx context.Context) error {
+	if t.interval == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	start := t.next
+	if start.Before(now) {
+		start = now
+	}
+	t.next = start.Add(t.interval)
+	t.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		t.cancel(start)
+		return ctx.Err()
+	}
+}
+
+// cancel gives back a reservation made for start that will not be used, so
+// callers that leave do not delay the ones that come after them. Like
+// rate.Limiter, it hands the time back to the schedule as a whole rather than
+// filling the exact gap; waiters already booked keep their turn.
+func (t *Throttle) cancel(start time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !start.After(time.Now()) {
+		return
+	}
+	t.next = t.next.Add(-t.interval)
+}
+
+func (t *Throttle) String() string {
+	return fmt.Sprint(t.Geocoder)
+}
@@ -0,0 +1,219 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// blockingGeocoder answers nairobi once release is closed, counting its lookups
+// and the most that ran at once. It gives up when its context ends.
+type blockingGeocoder struct {
+	release chan struct{}
+	calls   atomic.Int32
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (g *blockingGeocoder) Geocode(ctx context.Context, place models.Place) (Coordinates, error) {
+	g.calls.Add(1)
+	n := g.running.Add(1)
+	defer g.running.Add(-1)
+	for {
+		peak := g.peak.Load()
+		if n <= peak || g.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	select {
+	case <-g.release:
+		return nairobi, nil
+	case <-ctx.Done():
+		return Coordinates{}, ctx.Err()
+	}
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSingleFlightCoalesces(t *testing.T) {
+	inner := &blockingGeocoder{release: make(chan struct{})}
+	g := NewSingleFlight(inner)
+	place := models.ParsePlace("nairobi-kenya")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			coords, err := g.Geocode(context.Background(), place)
+			if err == nil && coords != nairobi {
+				err = errors.New("wrong coordinates")
+			}
+			errs <- err
+		}()
+	}
+	waitFor(t, func() bool { return inner.calls.Load() == 1 })
+	close(inner.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("expected every caller to get the shared result, got %v", err)
+		}
+	}
+	if n := inner.calls.Load(); n != 1 {
+		t.Errorf("expected 1 lookup for 10 concurrent callers, got %d", n)
+	}
+}
+
+func TestSingleFlightCancellation(t *testing.T) {
+	inner := &blockingGeocoder{release: make(chan struct{})}
+	g := NewSingleFlight(inner)
+	place := models.ParsePlace("nairobi-kenya")
+
+	// The first caller leaves, the second still gets the result
+	leaving, leave := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := g.Geocode(leaving, place)
+		first <- err
+	}()
+	waitFor(t, func() bool { return inner.calls.Load() == 1 })
+	second := make(chan error, 1)
+	go func() {
+		_, err := g.Geocode(context.Background(), place)
+		second <- err
+	}()
+	waitFor(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.calls[place.Slug()] != nil && g.calls[place.Slug()].waiters == 2
+	})
+
+	leave()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the leaving caller to get context.Canceled, got %v", err)
+	}
+	close(inner.release)
+	if err := <-second; err != nil {
+		t.Errorf("expected the remaining caller to get the result, got %v", err)
+	}
+
+	// When every caller leaves, the lookup itself is cancelled
+	inner = &blockingGeocoder{release: make(chan struct{})}
+	g = NewSingleFlight(inner)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		g.Geocode(ctx, place)
+		close(done)
+	}()
+	waitFor(t, func() bool { return inner.running.Load() == 1 })
+	cancel()
+	<-done
+	waitFor(t, func() bool { return inner.running.Load() == 0 })
+}
+
+func TestThrottleConcurrency(t *testing.T) {
+	inner := &blockingGeocoder{release: make(chan struct{})}
+	g := NewThrottle(inner, 2, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.Geocode(context.Background(), models.ParsePlace("nairobi-kenya"))
+		}()
+	}
+	waitFor(t, func() bool { return inner.running.Load() == 2 })
+	time.Sleep(10 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+
+	if peak := inner.peak.Load(); peak != 2 {
+		t.Errorf("expected at most 2 lookups at once, got %d", peak)
+	}
+	if n := inner.calls.Load(); n != 6 {
+		t.Errorf("expected every lookup to run eventually, got %d", n)
+	}
+}
+
+func TestThrottleRate(t *testing.T) {
+	inner := &countingGeocoder{table: Static{"nairobi-kenya": nairobi}}
+	g := NewThrottle(inner, 0, 100) // one lookup every 10ms
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		g.Geocode(context.Background(), models.ParsePlace("nairobi-kenya"))
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected 5 lookups to take at least 40ms at 100 per second, took %v", elapsed)
+	}
+
+	// A caller whose context ends while waiting makes no request
+	slow := NewThrottle(inner, 0, 1)
+	slow.Geocode(context.Background(), models.ParsePlace("nairobi-kenya"))
+	calls := inner.calls.Load()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := slow.Geocode(ctx, models.ParsePlace("nairobi-kenya")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if inner.calls.Load() != calls {
+		t.Errorf("expected no lookup after the context ended")
+	}
+}
+
+func TestThrottleCancelReturnsTurns(t *testing.T) {
+	inner := &countingGeocoder{table: Static{"nairobi-kenya": nairobi}}
+	g := NewThrottle(inner, 0, 10) // one lookup every 100ms
+	place := models.ParsePlace("nairobi-kenya")
+	g.Geocode(context.Background(), place)
+
+	// Ten callers book the next second of turns, then all leave
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.Geocode(ctx, place)
+		}()
+	}
+	waitFor(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return time.Until(g.next) > 900*time.Millisecond
+	})
+	cancel()
+	wg.Wait()
+
+	start := time.Now()
+	if _, err := g.Geocode(context.Background(), place); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("expected the turns of cancelled callers to be given back, waited %v", elapsed)
+	}
+	if n := inner.calls.Load(); n != 2 {
+		t.Errorf("expected 2 lookups, got %d", n)
+	}
+}
@@ -0,0 +1,34 @@
+package geocode
+
+import (
+	"context"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// flusher is a cache that holds results back before saving them, such as FileCache
+type flusher interface {
+	Flush() error
+}
+
+// Warm looks up every place with g, so a cache in front of the real geocoder
+// holds them all before the first visitor asks. It returns the places that
+// could not be geocoded, with their errors, and stops early when ctx is done.
+// A cache that saves its results is saved once at the end; err reports a
+// failure to do so.
+func Warm(ctx context.Context, g Geocoder, places []models.Place) (failed map[models.Place]error, err error) {
+	failed = map[models.Place]error{}
+	for _, place := range places {
+		if ctx.Err() != nil {
+			failed[place] = ctx.Err()
+			continue
+		}
+		if _, err := g.Geocode(ctx, place); err != nil {
+			failed[place] = err
+		}
+	}
+	if f, ok := g.(flusher); ok {
+		err = f.Flush()
+	}
+	return failed, err
+}
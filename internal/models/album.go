@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FirstAlbumDate parses FirstAlbum, which upstream sends as DD-MM-YYYY.
+// ok is false when the value is missing or malformed.
+func (a Artist) FirstAlbumDate() (date time.Time, ok bool) {
+	date, err := ParseDate(a.FirstAlbum)
+	return date, err == nil
+}
+
+// FirstAlbumYear returns the year of the first album, or 0 when it is unknown.
+func (a Artist) FirstAlbumYear() int {
+	date, ok := a.FirstAlbumDate()
+	if !ok {
+		return 0
+	}
+	return date.Year()
+}
+
+// FirstAlbumDisplay returns the first album date in a readable form such as
+// "14 Dec 1973", falling back to the raw upstream value.
+func (a Artist) FirstAlbumDisplay() string {
+	date, ok := a.FirstAlbumDate()
+	if !ok {
+		return a.FirstAlbum
+	}
+	return date.Format("2 Jan 2006")
+}
+
+// YearsToFirstAlbum returns how many years passed between the creation of the
+// band and its first album. ok is false when either date is unknown.
+func (a Artist) YearsToFirstAlbum() (years int, ok bool) {
+	year := a.FirstAlbumYear()
+	if year == 0 || a.CreationDate == 0 {
+		return 0, false
+	}
+	return year - a.CreationDate, true
+}
+
+// CompareFirstAlbum orders artists by first album date, oldest first.
+// Artists without a valid date sort after all others.
+func CompareFirstAlbum(a, b Artist) int {
+	dateA, okA := a.FirstAlbumDate()
+	dateB, okB := b.FirstAlbumDate()
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return 1
+	case !okB:
+		return -1
+	}
+	return dateA.Compare(dateB)
+}
+
+// YearRange is an inclusive range of years. A zero bound leaves that side open.
+type YearRange struct {
+	From int
+	To   int
+}
+
+// ParseYearRange parses "1973", "1970..1980", "1970.." or "..1980".
+func ParseYearRange(s string) (YearRange, error) {
+	s = strings.TrimSpace(s)
+	from, to, isRange := strings.Cut(s, "..")
+	if !isRange {
+		to = from
+	}
+
+	var r YearRange
+	var err error
+	if from != "" {
+		if r.From, err = parseYear(from); err != nil {
+			return YearRange{}, err
+		}
+	}
+	if to != "" {
+		if r.To, err = parseYear(to); err != nil {
+			return YearRange{}, err
+		}
+	}
+	if r.IsZero() {
+		return YearRange{}, fmt.Errorf("empty year range %q", s)
+	}
+	if r.From != 0 && r.To != 0 && r.From > r.To {
+		return YearRange{}, fmt.Errorf("year range %q ends before it starts", s)
+	}
+	return r, nil
+}
+
+func parseYear(s string) (int, error) {
+	year, err := strconv.Atoi(s)
+	if err != nil || len(s) != 4 {
+		return 0, fmt.Errorf("invalid year %q", s)
+	}
+	return year, nil
+}
+
+// IsZero reports whether the range is unbounded on both sides.
+func (r YearRange) IsZero() bool {
+	return r.From == 0 && r.To == 0
+}
+
+// Contains reports whether year falls inside the range. Year 0 (unknown) is
+// only contained in the unbounded range.
+func (r YearRange) Contains(year int) bool {
+	if r.IsZero() {
+		return true
+	}
+	if year == 0 {
+		return false
+	}
+	return (r.From == 0 || year >= r.From) && (r.To == 0 || year <= r.To)
+}
+
+func (r YearRange) String() string {
+	switch {
+	case r.From == r.To:
+		return strconv.Itoa(r.From)
+	case r.From == 0:
+		return ".." + strconv.Itoa(r.To)
+	case r.To == 0:
+		return strconv.Itoa(r.From) + ".."
+	}
+	return strconv.Itoa(r.From) + ".." + strconv.Itoa(r.To)
+}
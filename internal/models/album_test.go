@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestFirstAlbumDate(t *testing.T) {
+	artist := Artist{Name: "Queen", CreationDate: 1970, FirstAlbum: "14-12-1973"}
+
+	if got := artist.FirstAlbumYear(); got != 1973 {
+		t.Errorf("expected first album year 1973, got %d", got)
+	}
+	if got := artist.FirstAlbumDisplay(); got != "14 Dec 1973" {
+		t.Errorf("expected '14 Dec 1973', got %q", got)
+	}
+	if years, ok := artist.YearsToFirstAlbum(); !ok || years != 3 {
+		t.Errorf("expected 3 years to the first album, got %d (ok %v)", years, ok)
+	}
+
+	unknown := Artist{Name: "Unknown", FirstAlbum: "soon"}
+	if _, ok := unknown.FirstAlbumDate(); ok {
+		t.Errorf("expected a malformed first album date to be reported")
+	}
+	if got := unknown.FirstAlbumDisplay(); got != "soon" {
+		t.Errorf("expected the raw value as fallback, got %q", got)
+	}
+	if CompareFirstAlbum(artist, unknown) >= 0 {
+		t.Errorf("expected artists without a first album date to sort last")
+	}
+}
+
+func TestParseYearRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected YearRange
+	}{
+		{"1973", YearRange{1973, 1973}},
+		{"1970..1980", YearRange{1970, 1980}},
+		{"1970..", YearRange{From: 1970}},
+		{"..1980", YearRange{To: 1980}},
+	}
+	for _, tt := range tests {
+		r, err := ParseYearRange(tt.input)
+		if err != nil {
+			t.Errorf("ParseYearRange(%q): expected no error, got %v", tt.input, err)
+			continue
+		}
+		if r != tt.expected {
+			t.Errorf("ParseYearRange(%q): expected %+v, got %+v", tt.input, tt.expected, r)
+		}
+		if r.String() != tt.input {
+			t.Errorf("expected %+v to print as %q, got %q", r, tt.input, r.String())
+		}
+	}
+
+	for _, input := range []string{"", "..", "73", "1980..1970", "abcd"} {
+		if _, err := ParseYearRange(input); err == nil {
+			t.Errorf("ParseYearRange(%q): expected an error", input)
+		}
+	}
+
+	r := YearRange{From: 1970, To: 1980}
+	if !r.Contains(1975) || r.Contains(1981) || r.Contains(0) {
+		t.Errorf("expected %v to contain only years from 1970 to 1980", r)
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// DateLayout is the DD-MM-YYYY format upstream uses for concert and album dates.
+const DateLayout = "02-01-2006"
+
+// ParseDate parses an upstream date such as "23-08-2019", ignoring the "*"
+// prefix upstream puts in front of some concert dates.
+func ParseDate(s string) (time.Time, error) {
+	return time.Parse(DateLayout, strings.TrimPrefix(strings.TrimSpace(s), "*"))
+}
+
+// Concert is one show of an artist, built from the relations data.
+type Concert struct {
+	Date     time.Time
+	Location string // upstream slug, e.g. "los_angeles-usa"
+	Place    Place
+	Starred  bool // upstream listed the date with a leading "*" in Date.Dates
+}
+
+// Upcoming reports whether the concert takes place on or after the day of now.
+func (c Concert) Upcoming(now time.Time) bool {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return !c.Date.Before(today)
+}
+
+// ISODate returns the concert date as YYYY-MM-DD, suitable for a <time datetime> attribute.
+func (c Concert) ISODate() string {
+	return c.Date.Format("2006-01-02")
+}
+
+// DisplayDate returns the concert date in a readable form such as "Fri 23 Aug 2019".
+func (c Concert) DisplayDate() string {
+	return c.Date.Format("Mon 2 Jan 2006")
+}
+
+// Concerts lists the shows in relation in chronological order, ties broken by
+// location. The "*" flags are taken from dates, the artist's Date entry.
+// Dates that cannot be parsed are skipped.
+func Concerts(relation Relation, dates Date) []Concert {
+	starred := map[string]bool{}
+	for _, value := range dates.Dates {
+		if strings.HasPrefix(value, "*") {
+			starred[strings.TrimPrefix(value, "*")] = true
+		}
+	}
+
+	var concerts []Concert
+	for location, values := range relation.DatesLocations {
+		for _, value := range values {
+			date, err := ParseDate(value)
+			if err != nil {
+				continue
+			}
+			concerts = append(concerts, Concert{
+				Date:     date,
+				Location: location,
+				Place:    ParsePlace(location),
+				Starred:  strings.HasPrefix(value, "*") || starred[strings.TrimPrefix(value, "*")],
+			})
+		}
+	}
+
+	sort.Slice(concerts, func(i, j int) bool {
+		if !concerts[i].Date.Equal(concerts[j].Date) {
+			return concerts[i].Date.Before(concerts[j].Date)
+		}
+		return concerts[i].Location < concerts[j].Location
+	})
+	return concerts
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	date, err := ParseDate("*23-08-2019")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if date.Year() != 2019 || date.Month() != time.August || date.Day() != 23 {
+		t.Errorf("expected 2019-08-23, got %v", date)
+	}
+
+	if _, err := ParseDate("2019-08-23"); err == nil {
+		t.Errorf("expected an error for a date not in DD-MM-YYYY")
+	}
+}
+
+func TestConcerts(t *testing.T) {
+	relation := Relation{
+		ID: 1,
+		DatesLocations: map[string][]string{
+			"los_angeles-usa": {"20-08-2019"},
+			"osaka-japan":     {"28-01-2020"},
+			"georgia-usa":     {"22-08-2019", "not a date"},
+		},
+	}
+	dates := Date{ID: 1, Dates: []string{"*20-08-2019", "22-08-2019", "*28-01-2020"}}
+
+	concerts := Concerts(relation, dates)
+	if len(concerts) != 3 {
+		t.Fatalf("expected 3 concerts, got %d", len(concerts))
+	}
+
+	expected := []struct {
+		location string
+		place    Place
+		starred  bool
+	}{
+		{"los_angeles-usa", Place{City: "Los Angeles", Country: "United States"}, true},
+		{"georgia-usa", Place{City: "Georgia", Country: "United States"}, false},
+		{"osaka-japan", Place{City: "Osaka", Country: "Japan"}, true},
+	}
+	for i, e := range expected {
+		c := concerts[i]
+		if c.Location != e.location || c.Place != e.place || c.Starred != e.starred {
+			t.Errorf("concert %d: expected %s (%s, starred %v), got %s (%s, starred %v)",
+				i, e.location, e.place, e.starred, c.Location, c.Place, c.Starred)
+		}
+	}
+
+	now := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+	if concerts[0].Upcoming(now) || !concerts[2].Upcoming(now) {
+		t.Errorf("expected only the 2020 concert to be upcoming on %v", now)
+	}
+	if got := concerts[0].DisplayDate(); got != "Tue 20 Aug 2019" {
+		t.Errorf("expected display date 'Tue 20 Aug 2019', got %q", got)
+	}
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateRange is an inclusive range of days. A zero bound leaves that side open.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// dateRangeLayouts are the forms accepted for each side of a DateRange, with
+// the period each one covers
+var dateRangeLayouts = []struct {
+	layout string
+	years  int
+	months int
+	days   int
+}{
+	{"2006", 1, 0, 0},
+	{"01-2006", 0, 1, 0},
+	{"2006-01", 0, 1, 0},
+	{DateLayout, 0, 0, 1},
+	{"2006-01-02", 0, 0, 1},
+}
+
+// ParseDateRange parses a day, month or year, such as "2019", "08-2019",
+// "2019-08", "23-08-2019" or "2019-08-23", or a range between two of them
+// such as "2019-08-01..2019-09-01". Either side of a range may be left open.
+// A month or year covers all of its days.
+func ParseDateRange(s string) (DateRange, error) {
+	s = strings.TrimSpace(s)
+	from, to, isRange := strings.Cut(s, "..")
+	if !isRange {
+		to = from
+	}
+
+	var r DateRange
+	if from != "" {
+		start, _, err := parsePeriod(from)
+		if err != nil {
+			return DateRange{}, err
+		}
+		r.From = start
+	}
+	if to != "" {
+		_, end, err := parsePeriod(to)
+		if err != nil {
+			return DateRange{}, err
+		}
+		r.To = end
+	}
+	if r.IsZero() {
+		return DateRange{}, fmt.Errorf("empty date range %q", s)
+	}
+	if !r.From.IsZero() && !r.To.IsZero() && r.To.Before(r.From) {
+		return DateRange{}, fmt.Errorf("date range %q ends before it starts", s)
+	}
+	return r, nil
+}
+
+// parsePeriod parses a day, month or year, returning its first and last day
+func parsePeriod(s string) (first, last time.Time, err error) {
+	for _, l := range dateRangeLayouts {
+		if len(s) != len(l.layout) {
+			continue
+		}
+		if first, err = time.Parse(l.layout, s); err == nil {
+			return first, first.AddDate(l.years, l.months, l.days-1), nil
+		}
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// IsZero reports whether the range is unbounded on both sides.
+func (r DateRange) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+// Contains reports whether the day of t falls inside the range.
+func (r DateRange) Contains(t time.Time) bool {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return (r.From.IsZero() || !day.Before(r.From)) && (r.To.IsZero() || !day.After(r.To))
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateRange(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	tests := []struct {
+		input    string
+		expected DateRange
+	}{
+		{"2019", DateRange{day("2019-01-01"), day("2019-12-31")}},
+		{"08-2019", DateRange{day("2019-08-01"), day("2019-08-31")}},
+		{"2020-02", DateRange{day("2020-02-01"), day("2020-02-29")}},
+		{"23-08-2019", DateRange{day("2019-08-23"), day("2019-08-23")}},
+		{"2019-08-01..2019-09-01", DateRange{day("2019-08-01"), day("2019-09-01")}},
+		{"2019..", DateRange{From: day("2019-01-01")}},
+		{"..08-2019", DateRange{To: day("2019-08-31")}},
+	}
+	for _, tt := range tests {
+		got, err := ParseDateRange(tt.input)
+		if err != nil {
+			t.Errorf("ParseDateRange(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !got.From.Equal(tt.expected.From) || !got.To.Equal(tt.expected.To) {
+			t.Errorf("ParseDateRange(%q): expected %v..%v, got %v..%v", tt.input, tt.expected.From, tt.expected.To, got.From, got.To)
+		}
+	}
+
+	for _, input := range []string{"", "..", "queen", "19", "2019-13", "2019-09-01..2019-08-01"} {
+		if _, err := ParseDateRange(input); err == nil {
+			t.Errorf("ParseDateRange(%q): expected an error", input)
+		}
+	}
+}
+
+func TestDateRangeContains(t *testing.T) {
+	r, _ := ParseDateRange("08-2019")
+	for _, tt := range []struct {
+		date     string
+		expected bool
+	}{
+		{"31-07-2019", false},
+		{"01-08-2019", true},
+		{"31-08-2019", true},
+		{"01-09-2019", false},
+	} {
+		date, _ := ParseDate(tt.date)
+		if got := r.Contains(date); got != tt.expected {
+			t.Errorf("Contains(%s): expected %v, got %v", tt.date, tt.expected, got)
+		}
+	}
+}
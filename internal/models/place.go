@@ -0,0 +1,93 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Place is a concert location parsed from an upstream slug such as
+// "los_angeles-usa" (city and country separated by "-", words by "_").
+type Place struct {
+	City    string
+	Country string
+}
+
+// countryNames maps country slugs that title-casing alone gets wrong to their display names.
+var countryNames = map[string]string{
+	"usa": "United States",
+	"uk":  "United Kingdom",
+	"uae": "United Arab Emirates",
+}
+
+// countrySlugs is the reverse of countryNames, used to round-trip a Place back to its slug.
+var countrySlugs = func() map[string]string {
+	slugs := make(map[string]string, len(countryNames))
+	for slug, name := range countryNames {
+		slugs[name] = slug
+	}
+	return slugs
+}()
+
+// lowerWords stay lowercase inside a name, as in "Playa del Carmen" or "Trinidad and Tobago".
+var lowerWords = map[string]bool{
+	"and": true, "of": true, "the": true, "de": true, "del": true, "da": true, "do": true, "la": true,
+}
+
+// ParsePlace parses an upstream location slug. The country is everything after
+// the last "-"; a slug without one is treated as a city with no country.
+func ParsePlace(slug string) Place {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	city, country := slug, ""
+	if i := strings.LastIndex(slug, "-"); i >= 0 {
+		city, country = slug[:i], slug[i+1:]
+	}
+
+	place := Place{City: titleCase(city)}
+	if name, ok := countryNames[country]; ok {
+		place.Country = name
+	} else {
+		place.Country = titleCase(country)
+	}
+	return place
+}
+
+// String returns the display name, e.g. "Los Angeles, United States".
+func (p Place) String() string {
+	if p.Country == "" {
+		return p.City
+	}
+	if p.City == "" {
+		return p.Country
+	}
+	return p.City + ", " + p.Country
+}
+
+// Slug returns the upstream form of the place, e.g. "los_angeles-usa".
+func (p Place) Slug() string {
+	country, ok := countrySlugs[p.Country]
+	if !ok {
+		country = toSlug(p.Country)
+	}
+	if country == "" {
+		return toSlug(p.City)
+	}
+	return toSlug(p.City) + "-" + country
+}
+
+// titleCase turns "playa_del_carmen" into "Playa del Carmen".
+func titleCase(s string) string {
+	words := strings.Fields(strings.ReplaceAll(s, "_", " "))
+	for i, word := range words {
+		if i > 0 && lowerWords[word] {
+			continue
+		}
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(first)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+func toSlug(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), " ", "_")
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestParsePlace(t *testing.T) {
+	tests := []struct {
+		slug     string
+		expected Place
+		display  string
+	}{
+		{"los_angeles-usa", Place{City: "Los Angeles", Country: "United States"}, "Los Angeles, United States"},
+		{"london-uk", Place{City: "London", Country: "United Kingdom"}, "London, United Kingdom"},
+		{"auckland-new_zealand", Place{City: "Auckland", Country: "New Zealand"}, "Auckland, New Zealand"},
+		{"playa_del_carmen-mexico", Place{City: "Playa del Carmen", Country: "Mexico"}, "Playa del Carmen, Mexico"},
+		{"saitama", Place{City: "Saitama"}, "Saitama"},
+		{"åarhus-denmark", Place{City: "Åarhus", Country: "Denmark"}, "Åarhus, Denmark"},
+		{"zürich-switzerland", Place{City: "Zürich", Country: "Switzerland"}, "Zürich, Switzerland"},
+	}
+
+	for _, tt := range tests {
+		place := ParsePlace(tt.slug)
+		if place != tt.expected {
+			t.Errorf("ParsePlace(%q): expected %+v, got %+v", tt.slug, tt.expected, place)
+		}
+		if got := place.String(); got != tt.display {
+			t.Errorf("ParsePlace(%q).String(): expected %q, got %q", tt.slug, tt.display, got)
+		}
+		if got := place.Slug(); got != tt.slug {
+			t.Errorf("ParsePlace(%q).Slug(): expected the slug back, got %q", tt.slug, got)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package search
+
+import (
+	"strings"
+)
+
+// FieldOptions configures how one field is matched.
+type FieldOptions struct {
+	Weight   float64 // breaks ties between matches of the same kind in different fields
+	MaxEdits int     // largest edit distance accepted for a fuzzy match; 0 disables fuzzy matching
+}
+
+// Options configures an Engine.
+type Options struct {
+	Fields map[Field]FieldOptions
+}
+
+// DefaultOptions ranks artist names over members over locations, and
+// tolerates typos in names and places but not in dates.
+func DefaultOptions() Options {
+	return Options{Fields: map[Field]FieldOptions{
+		FieldArtist:       {Weight: 3, MaxEdits: 2},
+		FieldMember:       {Weight: 2, MaxEdits: 2},
+		FieldLocation:     {Weight: 1.5, MaxEdits: 1},
+		FieldFirstAlbum:   {Weight: 1},
+		FieldCreationDate: {Weight: 1},
+		FieldConcertDate:  {Weight: 1},
+	}}
+}
+
+// allowedEdits caps the edit distance for short queries, where a single typo
+// already turns one word into another ("may" and "mac")
+func allowedEdits(query string, maxEdits int) int {
+	n := len([]rune(query))
+	switch {
+	case n < 4:
+		return 0
+	case n < 6:
+		return min(maxEdits, 1)
+	}
+	return maxEdits
+}
+
+// FuzzyMatch returns the smallest edit distance between query and either text
+// or one of its words, if it is at most maxEdits. Both must already be folded.
+func FuzzyMatch(query, text string, maxEdits int) (edits int, ok bool) {
+	maxEdits = allowedEdits(query, maxEdits)
+	if maxEdits == 0 {
+		return 0, false
+	}
+
+	best := maxEdits + 1
+	candidates := append([]string{text}, splitWords(text)...)
+	for _, candidate := range candidates {
+		if d := boundedDistance(query, candidate, best-1); d < best {
+			best = d
+		}
+	}
+	return best, best <= maxEdits
+}
+
+// boundedDistance returns the optimal string alignment distance between a
+// and b (insertions, deletions, substitutions and adjacent transpositions),
+// or limit+1 as soon as it is known to exceed limit.
+func boundedDistance(a, b string, limit int) int {
+	if limit < 0 {
+		return 0
+	}
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > limit {
+		return limit + 1
+	}
+	if strings.EqualFold(a, b) {
+		return 0
+	}
+
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return min(prev[len(rb)], limit+1)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"testing"
+
+	"groupie-tracker-search-bar/internal/catalog"
+	"groupie-tracker-search-bar/internal/models"
+)
+
+func TestBoundedDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		limit    int
+		expected int
+	}{
+		{"metalica", "metallica", 2, 1},
+		{"qeeun", "queen", 2, 2},
+		{"freddie", "fredide", 2, 1}, // adjacent transposition
+		{"queen", "abba", 2, 3},      // stops at limit+1
+		{"same", "same", 2, 0},
+	}
+	for _, tt := range tests {
+		if got := boundedDistance(tt.a, tt.b, tt.limit); got != tt.expected {
+			t.Errorf("boundedDistance(%q, %q, %d): expected %d, got %d", tt.a, tt.b, tt.limit, tt.expected, got)
+		}
+	}
+}
+
+func TestFuzzySearch(t *testing.T) {
+	c := catalog.New(models.Dataset{
+		Artists: []models.Artist{
+			{ID: 1, Name: "Metallica", Members: []string{"James Hetfield"}, CreationDate: 1981, FirstAlbum: "25-07-1983"},
+			{ID: 2, Name: "Beyoncé", CreationDate: 1990, FirstAlbum: "24-06-2003"},
+			{ID: 3, Name: "Queen", Members: []string{"Freddie Mercury"}, CreationDate: 1970, FirstAlbum: "14-12-1973"},
+			{ID: 4, Name: "Queenadreena", CreationDate: 1997, FirstAlbum: "01-01-2000"},
+		},
+	})
+	engine := NewEngine(c, DefaultOptions())
+
+	tests := []struct {
+		query    string
+		expected int
+		kind     MatchKind
+	}{
+		{"metalica", 1, Fuzzy},
+		{"beyonce", 2, Exact},        // diacritic folding, not a typo
+		{"qeen", 3, Fuzzy},           // one missing letter
+		{"fredie mercury", 3, Fuzzy}, // typo in a member name
+	}
+	for _, tt := range tests {
+		results := engine.Search(tt.query)
+		if len(results) == 0 {
+			t.Errorf("query %q: expected artist %d, got no results", tt.query, tt.expected)
+			continue
+		}
+		if results[0].ArtistID != tt.expected || results[0].Kind != tt.kind {
+			t.Errorf("query %q: expected artist %d (kind %v) first, got %+v", tt.query, tt.expected, tt.kind, results[0])
+		}
+	}
+
+	// Exact matches keep outranking fuzzy ones.
+	results := engine.Search("queen")
+	if results[0].ArtistID != 3 || results[0].Kind != Exact || results[1].ArtistID != 4 || results[1].Kind != Prefix {
+		t.Errorf("expected Queen then Queenadreena, got %+v", results)
+	}
+
+	// Dates are not fuzzy by default: 1971 is not a typo of 1981.
+	for _, r := range engine.Search("1971") {
+		t.Errorf("expected no match for 1971, got %+v", r)
+	}
+
+	// Fuzzy matching can be disabled per field.
+	opts := DefaultOptions()
+	opts.Fields[FieldArtist] = FieldOptions{Weight: 3}
+	if results := NewEngine(c, opts).Search("metalica"); len(results) != 0 {
+		t.Errorf("expected no results with fuzzy artist matching disabled, got %+v", results)
+	}
+}
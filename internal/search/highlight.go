@@ -0,0 +1,126 @@
+package search
+
+import (
+	utils "groupie-tracker-search-bar/internal/utilities"
+)
+
+// Span is a part of a result's text, from rune Start up to but excluding rune End.
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Segment is a piece of a result's text, highlighted if it matched the query.
+type Segment struct {
+	Text        string
+	Highlighted bool
+}
+
+// Segments splits the result's text at its highlights, for display.
+func (r Result) Segments() []Segment {
+	runes := []rune(r.Text)
+	var segments []Segment
+	last := 0
+	for _, span := range r.Highlights {
+		if span.Start > last {
+			segments = append(segments, Segment{Text: string(runes[last:span.Start])})
+		}
+		segments = append(segments, Segment{Text: string(runes[span.Start:span.End]), Highlighted: true})
+		last = span.End
+	}
+	if last < len(runes) {
+		segments = append(segments, Segment{Text: string(runes[last:])})
+	}
+	return segments
+}
+
+// Highlight returns the parts of text that a match of the given kind found
+// for query, which must already be folded. The query is located in the folded
+// text, preferring the start of the text, then the start of a word; a fuzzy
+// match highlights the closest word. An exact match that does not spell out
+// the text, such as a year range, highlights all of it.
+func Highlight(query, text string, kind MatchKind) []Span {
+	if kind == NoMatch || query == "" {
+		return nil
+	}
+	folded, origin := foldRunes(text)
+	if len(folded) == 0 {
+		return nil
+	}
+	span := func(start, end int) []Span {
+		return []Span{{Start: origin[start], End: origin[end-1] + 1}}
+	}
+	words := wordBounds(folded)
+
+	if kind == Fuzzy {
+		best, bestEdits := Span{0, len(folded)}, boundedDistance(query, string(folded), len(query))
+		for _, w := range words {
+			if d := boundedDistance(query, string(folded[w.Start:w.End]), len(query)); d < bestEdits {
+				best, bestEdits = w, d
+			}
+		}
+		return span(best.Start, best.End)
+	}
+
+	q := []rune(query)
+	if hasRunePrefix(folded, q) {
+		return span(0, len(q))
+	}
+	for _, w := range words {
+		if hasRunePrefix(folded[w.Start:], q) {
+			return span(w.Start, w.Start+len(q))
+		}
+	}
+	for i := range folded {
+		if hasRunePrefix(folded[i:], q) {
+			return span(i, i+len(q))
+		}
+	}
+	if kind == Exact {
+		return span(0, len(folded))
+	}
+	return nil
+}
+
+// foldRunes folds text one rune at a time, returning the folded runes and,
+// for each of them, the index of the rune of text it came from
+func foldRunes(text string) (folded []rune, origin []int) {
+	for i, r := range []rune(text) {
+		for _, f := range utils.Fold(string(r)) {
+			folded = append(folded, f)
+			origin = append(origin, i)
+		}
+	}
+	return folded, origin
+}
+
+// wordBounds returns the position of each word of text, split as splitWords does
+func wordBounds(text []rune) []Span {
+	var words []Span
+	start := -1
+	for i, r := range text {
+		switch {
+		case isSeparator(r) && start >= 0:
+			words = append(words, Span{start, i})
+			start = -1
+		case !isSeparator(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, Span{start, len(text)})
+	}
+	return words
+}
+
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(s) < len(prefix) {
+		return false
+	}
+	for i, r := range prefix {
+		if s[i] != r {
+			return false
+		}
+	}
+	return true
+}
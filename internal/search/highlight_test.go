@@ -0,0 +1,39 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		query, text string
+		kind        MatchKind
+		expected    []Span
+	}{
+		{"queen", "Queen", Exact, []Span{{0, 5}}},
+		{"mer", "Freddie Mercury", WordPrefix, []Span{{8, 11}}},
+		{"ddie", "Freddie Mercury", Substring, []Span{{3, 7}}},
+		{"beyonce", "Beyoncé", Exact, []Span{{0, 7}}},
+		{"maciwoda", "Paweł Mąciwoda", WordPrefix, []Span{{6, 14}}},
+		{"strasse", "Straße", Exact, []Span{{0, 6}}},
+		{"metalica", "Metallica", Fuzzy, []Span{{0, 9}}},
+		{"mercuri", "Freddie Mercury", Fuzzy, []Span{{8, 15}}},
+		{"1970..1980", "14-12-1973", Exact, []Span{{0, 10}}},
+		{"usa", "Los Angeles, United States", WordPrefix, nil},
+		{"abba", "Queen", NoMatch, nil},
+	}
+	for _, tt := range tests {
+		if got := Highlight(tt.query, tt.text, tt.kind); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Highlight(%q, %q): expected %v, got %v", tt.query, tt.text, tt.expected, got)
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	r := Result{Text: "Paweł Mąciwoda", Highlights: []Span{{6, 9}}}
+	expected := []Segment{{"Paweł ", false}, {"Mąc", true}, {"iwoda", false}}
+	if got := r.Segments(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
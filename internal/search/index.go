@@ -0,0 +1,253 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// gramSize is the length of the n-grams used to find substring matches.
+const gramSize = 3
+
+// index is an inverted index from folded terms to the units containing them.
+// Terms are every folded text and every word of it, so one sorted list serves
+// exact, prefix and word-prefix lookups; n-grams of the terms serve substring
+// lookups, and grouping terms by length bounds the fuzzy lookup.
+type index struct {
+	terms    []string         // sorted and distinct
+	postings [][]int          // units containing each term, ascending
+	grams    map[string][]int // n-gram to the terms containing it, ascending
+	byLength map[int][]int    // rune length to the terms of that length
+	albums   []yearEntry      // first album years, ascending
+	created  []yearEntry      // creation years, ascending
+	concerts []concertEntry   // concert dates, ascending
+}
+
+type yearEntry struct {
+	year  int
+	entry int
+}
+
+type concertEntry struct {
+	date    time.Time
+	entry   int
+	concert int
+}
+
+func buildIndex(e *Engine) *index {
+	units := map[string][]int{}
+	for id, u := range e.units {
+		for _, term := range u.terms {
+			words := append([]string{term}, splitWords(term)...)
+			for _, word := range words {
+				if list := units[word]; len(list) == 0 || list[len(list)-1] != id {
+					units[word] = append(list, id)
+				}
+			}
+		}
+	}
+
+	ix := &index{grams: map[string][]int{}, byLength: map[int][]int{}}
+	for term := range units {
+		ix.terms = append(ix.terms, term)
+	}
+	sort.Strings(ix.terms)
+	for id, term := range ix.terms {
+		ix.postings = append(ix.postings, units[term])
+		n := len([]rune(term))
+		ix.byLength[n] = append(ix.byLength[n], id)
+		for _, gram := range grams(term) {
+			if list := ix.grams[gram]; len(list) == 0 || list[len(list)-1] != id {
+				ix.grams[gram] = append(list, id)
+			}
+		}
+	}
+
+	for i, entry := range e.entries {
+		if year := entry.Artist.FirstAlbumYear(); year != 0 {
+			ix.albums = append(ix.albums, yearEntry{year, i})
+		}
+		if year := entry.Artist.CreationDate; year != 0 {
+			ix.created = append(ix.created, yearEntry{year, i})
+		}
+	}
+	for i, entry := range e.entries {
+		for j, concert := range entry.Concerts {
+			ix.concerts = append(ix.concerts, concertEntry{concert.Date, i, j})
+		}
+	}
+	sort.SliceStable(ix.concerts, func(i, j int) bool { return ix.concerts[i].date.Before(ix.concerts[j].date) })
+	byYear := func(years []yearEntry) func(i, j int) bool {
+		return func(i, j int) bool { return years[i].year < years[j].year }
+	}
+	sort.SliceStable(ix.albums, byYear(ix.albums))
+	sort.SliceStable(ix.created, byYear(ix.created))
+	return ix
+}
+
+// grams returns the distinct n-grams of s
+func grams(s string) []string {
+	runes := []rune(s)
+	var out []string
+	seen := map[string]bool{}
+	for i := 0; i+gramSize <= len(runes); i++ {
+		gram := string(runes[i : i+gramSize])
+		if !seen[gram] {
+			seen[gram] = true
+			out = append(out, gram)
+		}
+	}
+	return out
+}
+
+// candidates returns, in ascending order, the units that may match query:
+// those with a term that starts with query, contains it, or is within
+// maxEdits typos of it. Scoring them decides which really match.
+func (ix *index) candidates(query string, maxEdits int) []int {
+	matched := map[int]bool{}
+
+	// Prefix lookup: exact, prefix and word-prefix matches sit in one sorted range.
+	for i := sort.SearchStrings(ix.terms, query); i < len(ix.terms) && strings.HasPrefix(ix.terms[i], query); i++ {
+		matched[i] = true
+	}
+
+	// Substring lookup: terms sharing every n-gram of the query, checked for containment.
+	// Queries shorter than an n-gram fall back to checking every term.
+	var pool []int
+	if queryGrams := grams(query); len(queryGrams) > 0 {
+		pool = ix.intersectGrams(queryGrams)
+	} else {
+		pool = make([]int, len(ix.terms))
+		for i := range pool {
+			pool[i] = i
+		}
+	}
+	for _, id := range pool {
+		if !matched[id] && strings.Contains(ix.terms[id], query) {
+			matched[id] = true
+		}
+	}
+
+	// Fuzzy lookup: only terms whose length is within the edit budget can match.
+	if edits := allowedEdits(query, maxEdits); edits > 0 {
+		n := len([]rune(query))
+		for length := n - edits; length <= n+edits; length++ {
+			for _, id := range ix.byLength[length] {
+				if !matched[id] && boundedDistance(query, ix.terms[id], edits) <= edits {
+					matched[id] = true
+				}
+			}
+		}
+	}
+
+	seen := map[int]bool{}
+	var units []int
+	for id := range matched {
+		for _, u := range ix.postings[id] {
+			if !seen[u] {
+				seen[u] = true
+				units = append(units, u)
+			}
+		}
+	}
+	sort.Ints(units)
+	return units
+}
+
+// intersectGrams returns the terms containing every one of queryGrams
+func (ix *index) intersectGrams(queryGrams []string) []int {
+	lists := make([][]int, 0, len(queryGrams))
+	for _, gram := range queryGrams {
+		list, ok := ix.grams[gram]
+		if !ok {
+			return nil
+		}
+		lists = append(lists, list)
+	}
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+
+	result := lists[0]
+	for _, list := range lists[1:] {
+		var next []int
+		i, j := 0, 0
+		for i < len(result) && j < len(list) {
+			switch {
+			case result[i] == list[j]:
+				next = append(next, result[i])
+				i++
+				j++
+			case result[i] < list[j]:
+				i++
+			default:
+				j++
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// albumYears returns, by ascending entry, the first albums whose year is in
+// the range query describes, if query is a range such as "1970..1980"
+func (ix *index) albumYears(query string) []fieldMatch {
+	if !strings.Contains(query, "..") {
+		return nil
+	}
+	r, err := models.ParseYearRange(query)
+	if err != nil {
+		return nil
+	}
+	var matches []fieldMatch
+	for _, entry := range ix.yearsIn(FieldFirstAlbum, r) {
+		matches = append(matches, fieldMatch{entry: entry, field: FieldFirstAlbum})
+	}
+	return matches
+}
+
+// concertsIn returns the concerts whose date is in r, ordered by entry and
+// then by date
+func (ix *index) concertsIn(r models.DateRange) []fieldMatch {
+	start := 0
+	if !r.From.IsZero() {
+		start = sort.Search(len(ix.concerts), func(i int) bool { return !ix.concerts[i].date.Before(r.From) })
+	}
+	var matches []fieldMatch
+	for _, c := range ix.concerts[start:] {
+		if !r.Contains(c.date) {
+			break
+		}
+		matches = append(matches, fieldMatch{entry: c.entry, field: FieldConcertDate, concert: c.concert})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].entry != matches[j].entry {
+			return matches[i].entry < matches[j].entry
+		}
+		return matches[i].concert < matches[j].concert
+	})
+	return matches
+}
+
+// yearsIn returns, in ascending order, the entries whose first album or
+// creation year, as field says, is in r
+func (ix *index) yearsIn(field Field, r models.YearRange) []int {
+	years := ix.created
+	if field == FieldFirstAlbum {
+		years = ix.albums
+	}
+
+	start := 0
+	if r.From != 0 {
+		start = sort.Search(len(years), func(i int) bool { return years[i].year >= r.From })
+	}
+	var entries []int
+	for _, y := range years[start:] {
+		if r.To != 0 && y.year > r.To {
+			break
+		}
+		entries = append(entries, y.entry)
+	}
+	sort.Ints(entries)
+	return entries
+}
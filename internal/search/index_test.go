@@ -0,0 +1,95 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"groupie-tracker-search-bar/internal/catalog"
+	"groupie-tracker-search-bar/internal/fetch"
+	"groupie-tracker-search-bar/internal/models"
+)
+
+var indexQueries = []string{
+	"queen", "que", "q", "mer", "ddie", "mercury", "mercuri", "qeen", "queenz",
+	"london", "londn", "angeles", "los_angeles-usa", "new zealand", "uk",
+	"1973", "14-12-1973", "12-1973", "197", "1970..1980", "..1990", "1990..",
+	"roger waters", "rogr", "maciwoda", "paweł", "stone age", "zzz", "a", "  ",
+	"2019", "08-2019", "2019-08", "23-08-2019", "2019-08-01..2019-09-01", "..2019-01", "2020..",
+}
+
+// largeCatalog returns n synthetic artists built from a small vocabulary,
+// so names, members and locations repeat the way a real catalog's do
+func largeCatalog(n int) *catalog.Catalog {
+	words := []string{"black", "stone", "river", "queen", "electric", "night", "silver", "wolf", "mercury", "echo"}
+	cities := []string{"london-uk", "los_angeles-usa", "berlin-germany", "sao_paulo-brazil", "playa_del_carmen-mexico", "queenstown-new_zealand"}
+
+	var d models.Dataset
+	for i := 0; i < n; i++ {
+		id := i + 1
+		d.Artists = append(d.Artists, models.Artist{
+			ID:           id,
+			Name:         fmt.Sprintf("%s %s %d", words[i%len(words)], words[(i/len(words))%len(words)], id),
+			Members:      []string{fmt.Sprintf("Member %s %d", words[(i+3)%len(words)], id), fmt.Sprintf("Player %d", id)},
+			CreationDate: 1950 + i%70,
+			FirstAlbum:   fmt.Sprintf("%02d-%02d-%d", 1+i%28, 1+i%12, 1955+i%70),
+		})
+		d.Locations.Index = append(d.Locations.Index, models.Location{
+			ID:        id,
+			Locations: []string{cities[i%len(cities)], cities[(i+2)%len(cities)]},
+		})
+		d.Relations.Index = append(d.Relations.Index, models.Relation{
+			ID: id,
+			DatesLocations: map[string][]string{
+				cities[i%len(cities)]:     {fmt.Sprintf("%02d-%02d-%d", 1+i%28, 1+i%12, 2015+i%10)},
+				cities[(i+2)%len(cities)]: {fmt.Sprintf("%02d-%02d-%d", 1+(i+7)%28, 1+(i+5)%12, 2015+(i+3)%10)},
+			},
+		})
+	}
+	return catalog.New(d)
+}
+
+func embeddedCatalog(t testing.TB) *catalog.Catalog {
+	d, err := fetch.FetchAllData(context.Background(), fetch.EmbeddedSource())
+	if err != nil {
+		t.Fatalf("loading embedded data: %v", err)
+	}
+	return catalog.New(d)
+}
+
+func TestIndexMatchesScan(t *testing.T) {
+	catalogs := map[string]*catalog.Catalog{
+		"test":     testCatalog(),
+		"embedded": embeddedCatalog(t),
+		"large":    largeCatalog(500),
+	}
+	for name, c := range catalogs {
+		engine := NewEngine(c, DefaultOptions())
+		for _, query := range indexQueries {
+			indexed, scanned := engine.Search(query), engine.Scan(query)
+			if !reflect.DeepEqual(indexed, scanned) {
+				t.Errorf("%s catalog, query %q: index returned %v, scan returned %v", name, query, indexed, scanned)
+			}
+		}
+	}
+}
+
+func benchmarkEngine(b *testing.B, search func(*Engine, string) []Result) {
+	for _, n := range []int{100, 1000, 10000} {
+		engine := NewEngine(largeCatalog(n), DefaultOptions())
+		b.Run(fmt.Sprintf("artists=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				search(engine, indexQueries[i%len(indexQueries)])
+			}
+		})
+	}
+}
+
+func BenchmarkScan(b *testing.B) {
+	benchmarkEngine(b, (*Engine).Scan)
+}
+
+func BenchmarkSearch(b *testing.B) {
+	benchmarkEngine(b, (*Engine).Search)
+}
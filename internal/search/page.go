@@ -0,0 +1,100 @@
+package search
+
+// Fields lists every field a result can come from.
+var Fields = []Field{FieldArtist, FieldMember, FieldLocation, FieldConcertDate, FieldFirstAlbum, FieldCreationDate}
+
+// IsField reports whether s names a field.
+func IsField(s string) bool {
+	for _, f := range Fields {
+		if string(f) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Page selects part of a result list.
+type Page struct {
+	Offset      int     // results to skip
+	Limit       int     // results to return at most; zero means all
+	PerCategory int     // results to keep at most per field; zero means no cap
+	Fields      []Field // fields to keep results from; empty means all
+}
+
+// Paginate applies p to results, which keep their order. The field filter and
+// the per-category cap are applied first, so total is the number of results a
+// client can page through, and offset and limit then select the page.
+func Paginate(results []Result, p Page) (page []Result, total int) {
+	if len(p.Fields) > 0 {
+		keep := map[Field]bool{}
+		for _, f := range p.Fields {
+			keep[f] = true
+		}
+		filtered := make([]Result, 0, len(results))
+		for _, r := range results {
+			if keep[r.Field] {
+				filtered = append(filtered, r)
+			}
+		}
+		results = filtered
+	}
+	if p.PerCategory > 0 {
+		counts := map[Field]int{}
+		capped := make([]Result, 0, len(results))
+		for _, r := range results {
+			if counts[r.Field] < p.PerCategory {
+				counts[r.Field]++
+				capped = append(capped, r)
+			}
+		}
+		results = capped
+	}
+
+	total = len(results)
+	start := min(max(p.Offset, 0), total)
+	end := total
+	if p.Limit > 0 {
+		end = min(start+p.Limit, total)
+	}
+	return results[start:end], total
+}
+
+// Group is a heading results are listed under, gathering related fields.
+type Group struct {
+	Name   string
+	Fields []Field
+}
+
+// Groups are the headings of a results page, in the order they are shown.
+var Groups = []Group{
+	{Name: "Artists", Fields: []Field{FieldArtist}},
+	{Name: "Members", Fields: []Field{FieldMember}},
+	{Name: "Locations", Fields: []Field{FieldLocation}},
+	{Name: "Dates", Fields: []Field{FieldConcertDate, FieldFirstAlbum, FieldCreationDate}},
+}
+
+// ResultGroup is a group with the results listed under it.
+type ResultGroup struct {
+	Group
+	Results []Result
+}
+
+// GroupResults lists results under their group, keeping their order within a
+// group and leaving out groups without results.
+func GroupResults(results []Result) []ResultGroup {
+	var groups []ResultGroup
+	for _, g := range Groups {
+		rg := ResultGroup{Group: g}
+		for _, r := range results {
+			for _, f := range g.Fields {
+				if r.Field == f {
+					rg.Results = append(rg.Results, r)
+				}
+			}
+		}
+		if len(rg.Results) > 0 {
+			groups = append(groups, rg)
+		}
+	}
+	return groups
+}
@@ -0,0 +1,61 @@
+package search
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	results := []Result{
+		{ArtistID: 1, Field: FieldArtist},
+		{ArtistID: 2, Field: FieldMember},
+		{ArtistID: 3, Field: FieldArtist},
+		{ArtistID: 4, Field: FieldArtist},
+		{ArtistID: 5, Field: FieldMember},
+	}
+	tests := []struct {
+		page     Page
+		expected []int
+		total    int
+	}{
+		{Page{}, []int{1, 2, 3, 4, 5}, 5},
+		{Page{Limit: 2}, []int{1, 2}, 5},
+		{Page{Offset: 3, Limit: 2}, []int{4, 5}, 5},
+		{Page{Offset: 4, Limit: 2}, []int{5}, 5},
+		{Page{Offset: 9, Limit: 2}, []int{}, 5},
+		{Page{PerCategory: 2}, []int{1, 2, 3, 5}, 4},
+		{Page{Offset: 1, Limit: 2, PerCategory: 1}, []int{2}, 2},
+		{Page{Fields: []Field{FieldMember}}, []int{2, 5}, 2},
+		{Page{Limit: 1, Fields: []Field{FieldMember, FieldLocation}}, []int{2}, 2},
+	}
+	for _, tt := range tests {
+		page, total := Paginate(results, tt.page)
+		ids := []int{}
+		for _, r := range page {
+			ids = append(ids, r.ArtistID)
+		}
+		if !reflect.DeepEqual(ids, tt.expected) || total != tt.total {
+			t.Errorf("Paginate(%+v): expected %v of %d, got %v of %d", tt.page, tt.expected, tt.total, ids, total)
+		}
+	}
+}
+
+func TestGroupResults(t *testing.T) {
+	results := []Result{
+		{ArtistID: 1, Field: FieldConcertDate},
+		{ArtistID: 2, Field: FieldArtist},
+		{ArtistID: 3, Field: FieldCreationDate},
+		{ArtistID: 4, Field: FieldArtist},
+	}
+	var got []string
+	for _, g := range GroupResults(results) {
+		for _, r := range g.Results {
+			got = append(got, fmt.Sprintf("%s:%d", g.Name, r.ArtistID))
+		}
+	}
+	expected := []string{"Artists:2", "Artists:4", "Dates:1", "Dates:3"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
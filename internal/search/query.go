@@ -0,0 +1,134 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// Query is a parsed search query: free text matched against every field, and
+// clauses restricting one field each. An artist must satisfy all of them.
+type Query struct {
+	Text    string
+	Clauses []Clause
+}
+
+// Clause restricts a search to one field, e.g. member:freddie or year:1970..1980.
+// Text fields are matched like free text; date fields by Years.
+type Clause struct {
+	Field Field
+	Value string
+	Years models.YearRange // set for FieldCreationDate and FieldFirstAlbum
+	Dates models.DateRange // set for FieldConcertDate
+}
+
+// qualifiers maps the names accepted before a colon to the field they search
+var qualifiers = map[string]Field{
+	"artist":   FieldArtist,
+	"name":     FieldArtist,
+	"member":   FieldMember,
+	"location": FieldLocation,
+	"year":     FieldCreationDate,
+	"created":  FieldCreationDate,
+	"album":    FieldFirstAlbum,
+	"date":     FieldConcertDate,
+	"concert":  FieldConcertDate,
+}
+
+// ParseError reports a malformed query and where in it the problem is.
+type ParseError struct {
+	Query  string
+	Offset int // in bytes from the start of Query
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid query at character %d: %s", len([]rune(e.Query[:e.Offset]))+1, e.Msg)
+}
+
+// IsZero reports whether q has nothing to search for.
+func (q Query) IsZero() bool {
+	return q.Text == "" && len(q.Clauses) == 0
+}
+
+// ParseQuery parses a query such as `queen member:"brian may" year:1970..1980`.
+// Words without a qualifier make up the free text; a value with spaces must be
+// quoted, and may be separated from its qualifier by spaces ("member: freddie").
+// Qualifiers are case-insensitive. A word before a colon that is not a
+// qualifier, as in "queen: live", is free text.
+func ParseQuery(s string) (Query, error) {
+	var q Query
+	var text []string
+
+	for i := 0; i < len(s); {
+		if s[i] == ' ' || s[i] == '\t' {
+			i++
+			continue
+		}
+		start := i
+
+		// A qualifier is a known word followed by a colon; anything else is free text.
+		var qualifier string
+		if end := strings.IndexFunc(s[i:], func(r rune) bool { return !unicode.IsLetter(r) }); end > 0 && s[i+end] == ':' {
+			if _, ok := qualifiers[strings.ToLower(s[i:i+end])]; ok {
+				qualifier = strings.ToLower(s[i : i+end])
+				i += end + 1
+				for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+					i++
+				}
+			}
+		}
+
+		value, next, err := readValue(s, i)
+		if err != nil {
+			return Query{}, err
+		}
+		i = next
+
+		if qualifier == "" {
+			text = append(text, value)
+			continue
+		}
+		field := qualifiers[qualifier]
+		if strings.TrimSpace(value) == "" {
+			return Query{}, &ParseError{Query: s, Offset: start, Msg: fmt.Sprintf("%s: needs a value", qualifier)}
+		}
+		clause := Clause{Field: field, Value: value}
+		if field == FieldCreationDate || field == FieldFirstAlbum {
+			if clause.Years, err = models.ParseYearRange(value); err != nil {
+				return Query{}, &ParseError{Query: s, Offset: start, Msg: fmt.Sprintf("%s: %v", qualifier, err)}
+			}
+		}
+		if field == FieldConcertDate {
+			if clause.Dates, err = models.ParseDateRange(value); err != nil {
+				return Query{}, &ParseError{Query: s, Offset: start, Msg: fmt.Sprintf("%s: %v", qualifier, err)}
+			}
+		}
+		q.Clauses = append(q.Clauses, clause)
+	}
+
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// readValue reads the word or quoted phrase starting at s[i], returning it and
+// the offset just past it
+func readValue(s string, i int) (value string, next int, err error) {
+	if i < len(s) && s[i] == '"' {
+		end := strings.IndexByte(s[i+1:], '"')
+		if end < 0 {
+			return "", 0, &ParseError{Query: s, Offset: i, Msg: "unterminated quote"}
+		}
+		return s[i+1 : i+1+end], i + end + 2, nil
+	}
+	end := strings.IndexAny(s[i:], " \t")
+	if end < 0 {
+		end = len(s) - i
+	}
+	if quote := strings.IndexByte(s[i:i+end], '"'); quote >= 0 {
+		return "", 0, &ParseError{Query: s, Offset: i + quote, Msg: "quote in the middle of a word"}
+	}
+	return s[i : i+end], i + end, nil
+}
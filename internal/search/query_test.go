@@ -0,0 +1,98 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected Query
+	}{
+		{"queen", Query{Text: "queen"}},
+		{"  pink   floyd ", Query{Text: "pink floyd"}},
+		{"member:freddie", Query{Clauses: []Clause{{Field: FieldMember, Value: "freddie"}}}},
+		{`Member:"brian may" location:london`, Query{Clauses: []Clause{
+			{Field: FieldMember, Value: "brian may"},
+			{Field: FieldLocation, Value: "london"},
+		}}},
+		{"queen year:1970..1980 album:1973", Query{Text: "queen", Clauses: []Clause{
+			{Field: FieldCreationDate, Value: "1970..1980", Years: models.YearRange{From: 1970, To: 1980}},
+			{Field: FieldFirstAlbum, Value: "1973", Years: models.YearRange{From: 1973, To: 1973}},
+		}}},
+		{`"stone age" 10:30`, Query{Text: "stone age 10:30"}},
+		{"member: freddie", Query{Clauses: []Clause{{Field: FieldMember, Value: "freddie"}}}},
+		{"queen: live", Query{Text: "queen: live"}},
+		{"genre:rock", Query{Text: "genre:rock"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseQuery(%q): unexpected error: %v", tt.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ParseQuery(%q): expected %+v, got %+v", tt.query, tt.expected, got)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query  string
+		offset int
+	}{
+		{"queen member:", 6},
+		{"queen member:  ", 6},
+		{`member:"brian may`, 7},
+		{`bri"an`, 3},
+		{"year:seventies", 0},
+		{"album:1980..1970", 0},
+		{"queen date:someday", 6},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.query)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("ParseQuery(%q): expected a *ParseError, got %v", tt.query, err)
+			continue
+		}
+		if parseErr.Offset != tt.offset {
+			t.Errorf("ParseQuery(%q): expected the error at offset %d, got %d (%v)", tt.query, tt.offset, parseErr.Offset, err)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	engine := NewEngine(testCatalog(), DefaultOptions())
+	tests := []struct {
+		query    string
+		expected []int
+	}{
+		{"member:freddie", []int{1}},
+		{"member:queen", nil},
+		{"location:london", []int{4, 3}},
+		{"year:1960..1990", []int{2, 4, 1}},
+		{"album:1973", []int{1}},
+		{"queen year:1990..", []int{3}},
+		{"location:london year:..1970", []int{4}},
+		{`member:"josh homme" album:1998`, []int{3}},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q): %v", tt.query, err)
+		}
+		var ids []int
+		for _, r := range engine.Find(q) {
+			ids = append(ids, r.ArtistID)
+		}
+		if !reflect.DeepEqual(ids, tt.expected) {
+			t.Errorf("Find(%q): expected artists %v, got %v", tt.query, tt.expected, ids)
+		}
+	}
+}
@@ -0,0 +1,407 @@
+package search
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"groupie-tracker-search-bar/internal/catalog"
+	"groupie-tracker-search-bar/internal/models"
+	utils "groupie-tracker-search-bar/internal/utilities"
+)
+
+// Field identifies the part of an artist a match was found in.
+type Field string
+
+const (
+	FieldArtist       Field = "artist"
+	FieldMember       Field = "member"
+	FieldFirstAlbum   Field = "first_album"
+	FieldCreationDate Field = "creation_date"
+	FieldLocation     Field = "location"
+	FieldConcertDate  Field = "concert_date"
+)
+
+// MatchKind describes how well a query matched a text. Higher is better.
+type MatchKind int
+
+const (
+	NoMatch    MatchKind = iota
+	Fuzzy                // the query is within a few typos of the text or one of its words
+	Substring            // the query appears inside the text
+	WordPrefix           // a word of the text starts with the query
+	Prefix               // the text starts with the query
+	Exact                // the text equals the query
+)
+
+// Result is one artist found by a search, described by its best match.
+type Result struct {
+	ArtistID   int
+	ArtistName string
+	Field      Field
+	Text       string // the matched text, e.g. a member name or a place
+	Kind       MatchKind
+	Edits      int               // typos corrected by a fuzzy match
+	Highlights []Span            // the parts of Text that matched
+	Concerts   []*models.Concert // every concert matched, in date order, for FieldConcertDate
+	Score      float64
+}
+
+// Score ranks a match: the kind of match decides first, then the field weight,
+// then, for fuzzy matches, the number of edits.
+func Score(kind MatchKind, weight float64, edits int) float64 {
+	if kind == NoMatch {
+		return 0
+	}
+	return float64(kind)*10 + weight - float64(edits)
+}
+
+// Label describes the result for display, e.g. "Freddie Mercury - member of Queen".
+func (r Result) Label() string {
+	switch r.Field {
+	case FieldArtist:
+		return r.ArtistName + " - artist/band"
+	case FieldMember:
+		return r.Text + " - member of " + r.ArtistName
+	case FieldFirstAlbum:
+		return r.Text + " - first album date of " + r.ArtistName
+	case FieldCreationDate:
+		return r.Text + " - creation date of " + r.ArtistName
+	case FieldLocation:
+		return r.ArtistName + " - " + r.Text
+	case FieldConcertDate:
+		if len(r.Concerts) > 0 {
+			label := r.Text + " - " + r.ArtistName + " in " + r.Concerts[0].Place.String()
+			for _, c := range r.Concerts[1:] {
+				label += "; " + c.Date.Format(models.DateLayout) + " in " + c.Place.String()
+			}
+			return label
+		}
+	}
+	return r.ArtistName
+}
+
+// Match reports how query matches text, ignoring case and diacritics.
+// query must already be folded with utils.Fold. Match never returns Fuzzy.
+func Match(query, text string) MatchKind {
+	return matchFolded(query, utils.Fold(text))
+}
+
+// matchFolded is Match for a text that is already folded
+func matchFolded(query, text string) MatchKind {
+	switch {
+	case query == "":
+		return NoMatch
+	case text == query:
+		return Exact
+	case strings.HasPrefix(text, query):
+		return Prefix
+	}
+	for _, word := range splitWords(text) {
+		if strings.HasPrefix(word, query) {
+			return WordPrefix
+		}
+	}
+	if strings.Contains(text, query) {
+		return Substring
+	}
+	return NoMatch
+}
+
+// splitWords splits text on spaces and the separators used in names, dates and location slugs
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, isSeparator)
+}
+
+func isSeparator(r rune) bool {
+	return r == ' ' || r == '-' || r == '_' || r == ',' || r == '.'
+}
+
+// unit is one searchable text of an artist, such as its name or one member.
+type unit struct {
+	entry int      // position in Engine.entries
+	field Field    // where the text comes from
+	text  string   // display form, reported in results
+	terms []string // folded forms to match: the text and any alternatives such as a location slug
+}
+
+// Engine runs searches against the entries of a catalog. It is immutable once
+// built, so one engine can serve concurrent requests; build a new one when the
+// catalog changes.
+type Engine struct {
+	entries []catalog.Entry
+	units   []unit
+	options Options
+	index   *index
+}
+
+// NewEngine returns an engine searching c with opts, indexing it up front.
+func NewEngine(c *catalog.Catalog, opts Options) *Engine {
+	e := &Engine{entries: c.Entries(), options: opts}
+	for i, entry := range e.entries {
+		artist := entry.Artist
+		e.addUnit(i, FieldArtist, artist.Name)
+		for _, member := range artist.Members {
+			e.addUnit(i, FieldMember, member)
+		}
+		e.addUnit(i, FieldFirstAlbum, artist.FirstAlbum)
+		e.addUnit(i, FieldCreationDate, strconv.Itoa(artist.CreationDate))
+		for _, location := range entry.Location.Locations {
+			e.addUnit(i, FieldLocation, models.ParsePlace(location).String(), location)
+		}
+	}
+	e.index = buildIndex(e)
+	return e
+}
+
+func (e *Engine) addUnit(entry int, field Field, text string, alternatives ...string) {
+	u := unit{entry: entry, field: field, text: text, terms: []string{utils.Fold(text)}}
+	for _, alt := range alternatives {
+		u.terms = append(u.terms, utils.Fold(alt))
+	}
+	e.units = append(e.units, u)
+}
+
+// Search returns at most one result per artist, its best match for query,
+// ordered from most to least relevant. Only the texts the index lists as
+// candidates for query are scored.
+func (e *Engine) Search(query string) []Result {
+	query = utils.Fold(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	matches := e.index.albumYears(query)
+	if r, err := models.ParseDateRange(query); err == nil {
+		matches = append(matches, e.index.concertsIn(r)...)
+	}
+	return e.rank(query, e.index.candidates(query, e.maxEdits()), matches)
+}
+
+// Scan is Search without the index: it scores every text of every artist.
+// It is kept as the reference the index is tested and benchmarked against.
+func (e *Engine) Scan(query string) []Result {
+	query = utils.Fold(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	units := make([]int, len(e.units))
+	for i := range units {
+		units[i] = i
+	}
+	var matches []fieldMatch
+	for i, entry := range e.entries {
+		if albumYearInRange(query, entry.Artist) {
+			matches = append(matches, fieldMatch{entry: i, field: FieldFirstAlbum})
+		}
+	}
+	if r, err := models.ParseDateRange(query); err == nil {
+		for i, entry := range e.entries {
+			for j, concert := range entry.Concerts {
+				if r.Contains(concert.Date) {
+					matches = append(matches, fieldMatch{entry: i, field: FieldConcertDate, concert: j})
+				}
+			}
+		}
+	}
+	return e.rank(query, units, matches)
+}
+
+// Find returns the artists matching every part of q: its free text, searched
+// as by Search, and each of its clauses. An artist is described by its best
+// match among them; results are ordered from most to least relevant.
+func (e *Engine) Find(q Query) []Result {
+	var parts [][]Result
+	if strings.TrimSpace(q.Text) != "" {
+		parts = append(parts, e.Search(q.Text))
+	}
+	for _, c := range q.Clauses {
+		parts = append(parts, e.searchClause(c))
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	best := map[int]Result{}
+	for _, r := range parts[0] {
+		best[r.ArtistID] = r
+	}
+	for _, part := range parts[1:] {
+		next := map[int]Result{}
+		for _, r := range part {
+			if prev, ok := best[r.ArtistID]; ok {
+				if prev.Score >= r.Score {
+					r = prev
+				}
+				next[r.ArtistID] = r
+			}
+		}
+		best = next
+	}
+
+	results := make([]Result, 0, len(best))
+	for _, r := range best {
+		results = append(results, r)
+	}
+	sortResults(results)
+	return results
+}
+
+// searchClause returns the artists matching a single clause
+func (e *Engine) searchClause(c Clause) []Result {
+	switch c.Field {
+	case FieldCreationDate, FieldFirstAlbum:
+		var matches []fieldMatch
+		for _, entry := range e.index.yearsIn(c.Field, c.Years) {
+			matches = append(matches, fieldMatch{entry: entry, field: c.Field})
+		}
+		return e.rank(utils.Fold(c.Value), nil, matches)
+	case FieldConcertDate:
+		return e.rank(utils.Fold(c.Value), nil, e.index.concertsIn(c.Dates))
+	}
+
+	query := utils.Fold(strings.TrimSpace(c.Value))
+	var units []int
+	for _, id := range e.index.candidates(query, e.options.Fields[c.Field].MaxEdits) {
+		if e.units[id].field == c.Field {
+			units = append(units, id)
+		}
+	}
+	return e.rank(query, units, nil)
+}
+
+// fieldMatch is an exact match of a whole field of an entry, found without
+// comparing texts, such as a year inside a range
+type fieldMatch struct {
+	entry   int
+	field   Field
+	concert int // index in the entry's Concerts, for FieldConcertDate
+}
+
+// rank scores the given units (in ascending order) and field matches,
+// keeping the best match per artist. Concerts matching as well as the best
+// match win the tie, and are all kept, so a date query tells every show.
+func (e *Engine) rank(query string, units []int, matches []fieldMatch) []Result {
+	best := map[int]*Result{}
+	consider := func(entry int, field Field, text string, kind MatchKind, edits int, concert *models.Concert) {
+		score := Score(kind, e.options.Fields[field].Weight, edits)
+		if score == 0 {
+			return
+		}
+		r, ok := best[entry]
+		if !ok {
+			artist := e.entries[entry].Artist
+			r = &Result{ArtistID: artist.ID, ArtistName: artist.Name}
+			best[entry] = r
+		}
+		if score > r.Score || (field == FieldConcertDate && score == r.Score && r.Field != field) {
+			r.Field, r.Text, r.Kind, r.Edits, r.Score, r.Concerts = field, text, kind, edits, score, nil
+		}
+		if field == FieldConcertDate && r.Field == field && score == r.Score {
+			r.Concerts = append(r.Concerts, concert)
+		}
+	}
+
+	for _, id := range units {
+		u := e.units[id]
+		kind, edits := e.matchUnit(query, u)
+		consider(u.entry, u.field, u.text, kind, edits, nil)
+	}
+	for _, m := range matches {
+		if m.field == FieldConcertDate {
+			concert := &e.entries[m.entry].Concerts[m.concert]
+			consider(m.entry, m.field, concert.Date.Format(models.DateLayout), Exact, 0, concert)
+			continue
+		}
+		consider(m.entry, m.field, e.fieldText(m.entry, m.field), Exact, 0, nil)
+	}
+
+	results := make([]Result, 0, len(best))
+	for _, r := range best {
+		if len(r.Concerts) > 0 {
+			sortConcerts(r.Concerts)
+			r.Text = r.Concerts[0].Date.Format(models.DateLayout)
+		}
+		r.Highlights = Highlight(query, r.Text, r.Kind)
+		results = append(results, *r)
+	}
+	sortResults(results)
+	return results
+}
+
+// fieldText is the text of a single-valued field of an entry
+func (e *Engine) fieldText(entry int, field Field) string {
+	artist := e.entries[entry].Artist
+	switch field {
+	case FieldArtist:
+		return artist.Name
+	case FieldFirstAlbum:
+		return artist.FirstAlbum
+	case FieldCreationDate:
+		return strconv.Itoa(artist.CreationDate)
+	}
+	return ""
+}
+
+// matchUnit tries the exact kinds first and only falls back to a fuzzy match
+// when the field allows it, so typo tolerance never outranks a real match
+func (e *Engine) matchUnit(query string, u unit) (MatchKind, int) {
+	kind := NoMatch
+	for _, term := range u.terms {
+		kind = max(kind, matchFolded(query, term))
+	}
+	if kind != NoMatch {
+		return kind, 0
+	}
+	if maxEdits := e.options.Fields[u.field].MaxEdits; maxEdits > 0 {
+		if edits, ok := FuzzyMatch(query, u.terms[0], maxEdits); ok {
+			return Fuzzy, edits
+		}
+	}
+	return NoMatch, 0
+}
+
+// maxEdits is the largest edit distance any field accepts
+func (e *Engine) maxEdits() int {
+	n := 0
+	for _, opts := range e.options.Fields {
+		n = max(n, opts.MaxEdits)
+	}
+	return n
+}
+
+// albumYearInRange reports whether query is a year range such as "1970..1980"
+// containing the year of the artist's first album
+func albumYearInRange(query string, artist models.Artist) bool {
+	if !strings.Contains(query, "..") {
+		return false
+	}
+	r, err := models.ParseYearRange(query)
+	return err == nil && r.Contains(artist.FirstAlbumYear())
+}
+
+// sortConcerts puts concerts in date order, ties broken by location, as
+// models.Concerts lists them
+func sortConcerts(concerts []*models.Concert) {
+	sort.Slice(concerts, func(i, j int) bool {
+		if !concerts[i].Date.Equal(concerts[j].Date) {
+			return concerts[i].Date.Before(concerts[j].Date)
+		}
+		return concerts[i].Location < concerts[j].Location
+	})
+}
+
+// sortResults orders results by score, then artist name, then ID, so equal
+// scores always come back in the same order
+func sortResults(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if c := utils.CompareFolded(a.ArtistName, b.ArtistName); c != 0 {
+			return c < 0
+		}
+		return a.ArtistID < b.ArtistID
+	})
+}
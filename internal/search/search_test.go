@@ -0,0 +1,147 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"groupie-tracker-search-bar/internal/catalog"
+	"groupie-tracker-search-bar/internal/models"
+)
+
+func testCatalog() *catalog.Catalog {
+	return catalog.New(models.Dataset{
+		Artists: []models.Artist{
+			{ID: 1, Name: "Queen", Members: []string{"Freddie Mercury", "Brian May"}, CreationDate: 1970, FirstAlbum: "14-12-1973"},
+			{ID: 2, Name: "Mercury Rev", Members: []string{"Jonathan Donahue"}, CreationDate: 1989, FirstAlbum: "01-06-1991"},
+			{ID: 3, Name: "Queens of the Stone Age", Members: []string{"Josh Homme"}, CreationDate: 1996, FirstAlbum: "06-10-1998"},
+			{ID: 4, Name: "Pink Floyd", Members: []string{"Roger Waters"}, CreationDate: 1965, FirstAlbum: "05-08-1967"},
+		},
+		Locations: models.LocationsData{Index: []models.Location{
+			{ID: 1, Locations: []string{"los_angeles-usa"}},
+			{ID: 2, Locations: []string{"queenstown-new_zealand"}},
+			{ID: 3, Locations: []string{"london-uk"}},
+			{ID: 4, Locations: []string{"london-uk"}},
+		}},
+	})
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		query, text string
+		expected    MatchKind
+	}{
+		{"queen", "Queen", Exact},
+		{"que", "Queen", Prefix},
+		{"mer", "Freddie Mercury", WordPrefix},
+		{"ddie", "Freddie Mercury", Substring},
+		{"angeles", "los_angeles-usa", WordPrefix},
+		{"abba", "Queen", NoMatch},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.query, tt.text); got != tt.expected {
+			t.Errorf("Match(%q, %q): expected %v, got %v", tt.query, tt.text, tt.expected, got)
+		}
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	engine := NewEngine(testCatalog(), DefaultOptions())
+
+	results := engine.Search("queen")
+	var ids []int
+	for _, r := range results {
+		ids = append(ids, r.ArtistID)
+	}
+	// Exact artist name, then artist name prefix, then a location word prefix.
+	expected := []int{1, 3, 2}
+	if len(ids) != len(expected) {
+		t.Fatalf("expected artists %v, got %v", expected, ids)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Fatalf("expected artists %v, got %v", expected, ids)
+		}
+	}
+
+	// Mercury is a member of Queen and the start of Mercury Rev's name: the artist name wins.
+	results = engine.Search("mercury")
+	if results[0].ArtistID != 2 || results[0].Field != FieldArtist {
+		t.Errorf("expected Mercury Rev as the top result, got %+v", results[0])
+	}
+	if results[1].Label() != "Freddie Mercury - member of Queen" {
+		t.Errorf("expected the member match second, got %q", results[1].Label())
+	}
+}
+
+func TestSearchDeduplicatesPerArtist(t *testing.T) {
+	// "london" matches two artists once each, and "o" matches many fields of every artist.
+	for _, query := range []string{"london", "o"} {
+		seen := map[int]bool{}
+		for _, r := range NewEngine(testCatalog(), DefaultOptions()).Search(query) {
+			if seen[r.ArtistID] {
+				t.Errorf("query %q: artist %d returned more than once", query, r.ArtistID)
+			}
+			seen[r.ArtistID] = true
+		}
+	}
+}
+
+func TestSearchFirstAlbumYearRange(t *testing.T) {
+	results := NewEngine(testCatalog(), DefaultOptions()).Search("1970..1975")
+	if len(results) != 1 || results[0].Label() != "14-12-1973 - first album date of Queen" {
+		t.Errorf("expected Queen's first album to match 1970..1975, got %+v", results)
+	}
+}
+
+func TestSearchConcertDates(t *testing.T) {
+	engine := NewEngine(embeddedCatalog(t), DefaultOptions())
+	tests := []struct {
+		query    string
+		expected []string // the label of each result
+	}{
+		{"08-2019", []string{
+			"20-08-2019 - Queen in Los Angeles, United States; 22-08-2019 in Georgia, United States; 23-08-2019 in North Carolina, United States",
+		}},
+		{"2019-12-06..2019-12-07", []string{
+			"06-12-2019 - Pink Floyd in Lausanne, Switzerland",
+			"06-12-2019 - SOJA in Playa del Carmen, Mexico; 07-12-2019 in Playa del Carmen, Mexico",
+		}},
+		{"date:2020-03", []string{"28-03-2020 - Scorpions in Berlin, Germany"}},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q): %v", tt.query, err)
+		}
+		var got []string
+		for _, r := range engine.Find(q) {
+			if r.Field != FieldConcertDate || len(r.Concerts) == 0 {
+				t.Errorf("%q: expected concert results, got %+v", tt.query, r)
+				continue
+			}
+			got = append(got, r.Label())
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%q: expected %v, got %v", tt.query, tt.expected, got)
+		}
+	}
+}
+
+func TestSearchConcertDatesOverAlbumYear(t *testing.T) {
+	// A year range matching both Queen's first album and two of its concerts lists the concerts
+	c := catalog.New(models.Dataset{
+		Artists: []models.Artist{{ID: 1, Name: "Queen", CreationDate: 1970, FirstAlbum: "14-12-1973"}},
+		Relations: models.RelationsData{Index: []models.Relation{{ID: 1, DatesLocations: map[string][]string{
+			"london-uk":       {"01-03-1974"},
+			"glasgow-uk":      {"10-11-1973"},
+			"hamburg-germany": {"05-05-1980"},
+		}}}},
+	})
+	results := NewEngine(c, DefaultOptions()).Search("1973..1974")
+	if len(results) != 1 || results[0].Field != FieldConcertDate {
+		t.Fatalf("expected one concert result, got %+v", results)
+	}
+	if got, expected := results[0].Label(), "10-11-1973 - Queen in Glasgow, United Kingdom; 01-03-1974 in London, United Kingdom"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,125 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"groupie-tracker-search-bar/internal/fetch"
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// Version is the snapshot file format written by this build.
+// Read refuses files with a newer or missing version.
+const Version = 1
+
+// Snapshot is an offline copy of the full artist dataset.
+// It implements fetch.DataSource so the server can boot from it.
+type Snapshot struct {
+	Version   int                  `json:"version"`
+	CreatedAt time.Time            `json:"createdAt"`
+	Source    string               `json:"source"`
+	Artists   []models.Artist      `json:"artists"`
+	Locations models.LocationsData `json:"locations"`
+	Dates     models.DatesData     `json:"dates"`
+	Relations models.RelationsData `json:"relation"`
+}
+
+// New wraps a dataset fetched from source in a snapshot stamped with the current time.
+func New(dataset models.Dataset, source string) Snapshot {
+	return Snapshot{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		Source:    source,
+		Artists:   dataset.Artists,
+		Locations: dataset.Locations,
+		Dates:     dataset.Dates,
+		Relations: dataset.Relations,
+	}
+}
+
+// Dataset returns the collections stored in the snapshot.
+func (s Snapshot) Dataset() models.Dataset {
+	return models.Dataset{
+		Artists:   s.Artists,
+		Locations: s.Locations,
+		Dates:     s.Dates,
+		Relations: s.Relations,
+	}
+}
+
+// Load copies one endpoint of the snapshot into target.
+func (s Snapshot) Load(ctx context.Context, endpoint string, target interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var part interface{}
+	switch endpoint {
+	case fetch.EndpointArtists:
+		part = s.Artists
+	case fetch.EndpointLocations:
+		part = s.Locations
+	case fetch.EndpointDates:
+		part = s.Dates
+	case fetch.EndpointRelation:
+		part = s.Relations
+	default:
+		return fmt.Errorf("snapshot has no endpoint %q", endpoint)
+	}
+
+	data, err := json.Marshal(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func (s Snapshot) String() string {
+	return fmt.Sprintf("snapshot v%d of %s taken %s", s.Version, s.Source, s.CreatedAt.Format(time.RFC3339))
+}
+
+// Write saves the snapshot to path. The file is written next to its
+// destination and renamed into place, so a failed export never leaves a
+// truncated snapshot behind.
+func Write(path string, s Snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Read loads a snapshot from path and checks that this build understands its version.
+func Read(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Snapshot{}, fmt.Errorf("decoding snapshot %s: %w", path, err)
+	}
+	if s.Version < 1 || s.Version > Version {
+		return Snapshot{}, fmt.Errorf("snapshot %s has unsupported version %d (this build reads up to %d)", path, s.Version, Version)
+	}
+	return s, nil
+}
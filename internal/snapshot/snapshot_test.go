@@ -0,0 +1,54 @@
+package snapshot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"groupie-tracker-search-bar/internal/fetch"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	dataset, err := fetch.FetchAllData(context.Background(), fetch.EmbeddedSource())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := Write(path, New(dataset, "embedded")); err != nil {
+		t.Fatalf("expected no error writing snapshot, got %v", err)
+	}
+
+	s, err := Read(path)
+	if err != nil {
+		t.Fatalf("expected no error reading snapshot, got %v", err)
+	}
+	if s.Version != Version || s.Source != "embedded" {
+		t.Errorf("expected version %d from 'embedded', got version %d from %q", Version, s.Version, s.Source)
+	}
+	if !reflect.DeepEqual(s.Dataset(), dataset) {
+		t.Errorf("expected the snapshot to round-trip the dataset")
+	}
+
+	// The snapshot must also work as a data source for startup.
+	loaded, err := fetch.FetchAllData(context.Background(), s)
+	if err != nil {
+		t.Fatalf("expected no error loading from snapshot, got %v", err)
+	}
+	if !reflect.DeepEqual(loaded, dataset) {
+		t.Errorf("expected loading from the snapshot to return the original dataset")
+	}
+}
+
+func TestReadUnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Read(path); err == nil {
+		t.Errorf("expected an error for an unsupported snapshot version")
+	}
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"strings"
+)
+
+// foldReplacer maps lowercase Latin letters with diacritics to their plain ASCII form
+var foldReplacer = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "ā", "a", "ă", "a", "ą", "a",
+	"ç", "c", "ć", "c", "č", "c",
+	"ď", "d", "đ", "d", "ð", "d",
+	"è", "e", "é", "e", "ê", "e", "ë", "e", "ē", "e", "ę", "e", "ě", "e",
+	"ğ", "g",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ī", "i", "ı", "i",
+	"ł", "l",
+	"ñ", "n", "ń", "n", "ň", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o", "ō", "o", "ő", "o",
+	"ř", "r",
+	"ś", "s", "š", "s", "ş", "s",
+	"ť", "t",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ū", "u", "ů", "u", "ű", "u",
+	"ý", "y", "ÿ", "y",
+	"ź", "z", "ż", "z", "ž", "z",
+	"ß", "ss", "æ", "ae", "œ", "oe", "þ", "th",
+)
+
+// Fold lowercases s and strips diacritics, so "Beyoncé" and "beyonce" compare equal.
+func Fold(s string) string {
+	return foldReplacer.Replace(strings.ToLower(s))
+}
+
+// CompareFolded orders two strings the way a reader expects names to be
+// listed: ignoring case and diacritics first, then by their exact form.
+func CompareFolded(a, b string) int {
+	if c := strings.Compare(Fold(a), Fold(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	tests := map[string]string{
+		"Beyoncé":        "beyonce",
+		"Paweł Mąciwoda": "pawel maciwoda",
+		"Mötley Crüe":    "motley crue",
+		"QUEEN":          "queen",
+	}
+	for input, expected := range tests {
+		if got := Fold(input); got != expected {
+			t.Errorf("Fold(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestCompareFolded(t *testing.T) {
+	if CompareFolded("Édith", "Eagles") <= 0 {
+		t.Errorf("expected Édith to sort after Eagles")
+	}
+	if CompareFolded("abba", "Beyoncé") >= 0 {
+		t.Errorf("expected abba to sort before Beyoncé regardless of case")
+	}
+}
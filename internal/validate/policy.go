@@ -0,0 +1,118 @@
+package validate
+
+import (
+	"fmt"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// Policy decides what happens to a dataset with integrity issues.
+type Policy string
+
+const (
+	// Strict refuses any dataset with issues.
+	Strict Policy = "strict"
+	// Degrade drops artists whose entries are missing or duplicated and strips
+	// malformed dates and unknown relation locations from the rest.
+	Degrade Policy = "degrade"
+	// Warn serves the dataset as is, filling missing entries with empty ones.
+	Warn Policy = "warn"
+)
+
+// ParsePolicy converts a configuration value into a Policy.
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case Strict, Degrade, Warn:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown integrity policy %q (want strict, degrade or warn)", s)
+	}
+}
+
+// Apply checks d and returns a copy in which the locations, dates and relation
+// entries line up with the artists by position, following policy for any issues.
+// The report lists every issue found in d, whatever the policy.
+func Apply(d models.Dataset, policy Policy) (models.Dataset, Report, error) {
+	report := Check(d)
+	if policy == Strict && !report.OK() {
+		return models.Dataset{}, report, fmt.Errorf("dataset refused: %s", report.Summary())
+	}
+
+	ix := index(d)
+	duplicated := map[int]bool{}
+	for _, dup := range ix.duplicates {
+		duplicated[dup.id] = true
+	}
+
+	var out models.Dataset
+	seen := map[int]bool{}
+	for _, artist := range d.Artists {
+		id := artist.ID
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		li, hasLocations := ix.locations[id]
+		di, hasDates := ix.dates[id]
+		ri, hasRelation := ix.relations[id]
+		if policy == Degrade && (duplicated[id] || !hasLocations || !hasDates || !hasRelation) {
+			continue
+		}
+
+		location := models.Location{ID: id}
+		if hasLocations {
+			location = d.Locations.Index[li]
+		}
+		date := models.Date{ID: id}
+		if hasDates {
+			date = d.Dates.Index[di]
+		}
+		relation := models.Relation{ID: id}
+		if hasRelation {
+			relation = d.Relations.Index[ri]
+		}
+		if policy == Degrade {
+			date, relation = repair(location, date, relation)
+		}
+
+		out.Artists = append(out.Artists, artist)
+		out.Locations.Index = append(out.Locations.Index, location)
+		out.Dates.Index = append(out.Dates.Index, date)
+		out.Relations.Index = append(out.Relations.Index, relation)
+	}
+
+	return out, report, nil
+}
+
+// repair returns copies of date and relation without malformed dates and
+// without relation locations that are missing from location.
+func repair(location models.Location, date models.Date, relation models.Relation) (models.Date, models.Relation) {
+	fixedDate := models.Date{ID: date.ID}
+	for _, value := range date.Dates {
+		if _, err := models.ParseDate(value); err == nil {
+			fixedDate.Dates = append(fixedDate.Dates, value)
+		}
+	}
+
+	known := map[string]bool{}
+	for _, l := range location.Locations {
+		known[l] = true
+	}
+	fixedRelation := models.Relation{ID: relation.ID, DatesLocations: map[string][]string{}}
+	for place, dates := range relation.DatesLocations {
+		if !known[place] {
+			continue
+		}
+		var valid []string
+		for _, value := range dates {
+			if _, err := models.ParseDate(value); err == nil {
+				valid = append(valid, value)
+			}
+		}
+		if len(valid) > 0 {
+			fixedRelation.DatesLocations[place] = valid
+		}
+	}
+	return fixedDate, fixedRelation
+}
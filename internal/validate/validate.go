@@ -0,0 +1,183 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// Issue kinds reported by Check.
+const (
+	DuplicateID     = "duplicate-id"     // the same ID appears twice in one collection
+	MissingEntry    = "missing-entry"    // an artist has no locations, dates or relation entry
+	OrphanEntry     = "orphan-entry"     // a locations, dates or relation entry has no artist
+	UnknownLocation = "unknown-location" // a relation names a location absent from Location.Locations
+	MalformedDate   = "malformed-date"   // a date is not DD-MM-YYYY (optionally prefixed with "*")
+)
+
+// Issue describes one integrity problem found in a dataset.
+type Issue struct {
+	Kind       string
+	ArtistID   int
+	Collection string // "artists", "locations", "dates" or "relation"
+	Detail     string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: artist %d in %s: %s", i.Kind, i.ArtistID, i.Collection, i.Detail)
+}
+
+// Report lists every issue found by Check.
+type Report struct {
+	Issues []Issue
+}
+
+// OK reports whether the dataset has no issues.
+func (r Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Summary describes the report in one line, listing at most a few issues.
+func (r Report) Summary() string {
+	if r.OK() {
+		return "no integrity issues"
+	}
+	const shown = 3
+	var parts []string
+	for i, issue := range r.Issues {
+		if i == shown {
+			parts = append(parts, fmt.Sprintf("and %d more", len(r.Issues)-shown))
+			break
+		}
+		parts = append(parts, issue.String())
+	}
+	return fmt.Sprintf("%d integrity issues: %s", len(r.Issues), strings.Join(parts, "; "))
+}
+
+// Check cross-references the four collections of a dataset by artist ID.
+func Check(d models.Dataset) Report {
+	var r Report
+	add := func(kind string, id int, collection, format string, args ...interface{}) {
+		r.Issues = append(r.Issues, Issue{Kind: kind, ArtistID: id, Collection: collection, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	ix := index(d)
+	for _, dup := range ix.duplicates {
+		add(DuplicateID, dup.id, dup.collection, "ID appears more than once")
+	}
+
+	for _, artist := range d.Artists {
+		if _, ok := ix.locations[artist.ID]; !ok {
+			add(MissingEntry, artist.ID, "locations", "no locations entry")
+		}
+		if _, ok := ix.dates[artist.ID]; !ok {
+			add(MissingEntry, artist.ID, "dates", "no dates entry")
+		}
+		if _, ok := ix.relations[artist.ID]; !ok {
+			add(MissingEntry, artist.ID, "relation", "no relation entry")
+		}
+		if _, err := models.ParseDate(artist.FirstAlbum); err != nil {
+			add(MalformedDate, artist.ID, "artists", "first album date %q", artist.FirstAlbum)
+		}
+	}
+
+	for _, collection := range []struct {
+		name string
+		ids  map[int]int
+	}{{"locations", ix.locations}, {"dates", ix.dates}, {"relation", ix.relations}} {
+		for _, id := range sortedKeys(collection.ids) {
+			if _, ok := ix.artists[id]; !ok {
+				add(OrphanEntry, id, collection.name, "no artist with this ID")
+			}
+		}
+	}
+
+	for _, date := range d.Dates.Index {
+		for _, value := range date.Dates {
+			if _, err := models.ParseDate(value); err != nil {
+				add(MalformedDate, date.ID, "dates", "concert date %q", value)
+			}
+		}
+	}
+
+	for _, relation := range d.Relations.Index {
+		known := map[string]bool{}
+		if i, ok := ix.locations[relation.ID]; ok {
+			for _, location := range d.Locations.Index[i].Locations {
+				known[location] = true
+			}
+		}
+		for _, location := range sortedLocations(relation.DatesLocations) {
+			if !known[location] {
+				add(UnknownLocation, relation.ID, "relation", "location %q is not in the artist's locations", location)
+			}
+			for _, value := range relation.DatesLocations[location] {
+				if _, err := models.ParseDate(value); err != nil {
+					add(MalformedDate, relation.ID, "relation", "date %q at %s", value, location)
+				}
+			}
+		}
+	}
+
+	return r
+}
+
+// positions maps each ID of every collection to its first position.
+type positions struct {
+	artists, locations, dates, relations map[int]int
+	duplicates                           []duplicate
+}
+
+type duplicate struct {
+	id         int
+	collection string
+}
+
+func index(d models.Dataset) positions {
+	ix := positions{
+		artists:   map[int]int{},
+		locations: map[int]int{},
+		dates:     map[int]int{},
+		relations: map[int]int{},
+	}
+	record := func(m map[int]int, collection string, id, i int) {
+		if _, seen := m[id]; seen {
+			ix.duplicates = append(ix.duplicates, duplicate{id, collection})
+			return
+		}
+		m[id] = i
+	}
+	for i, a := range d.Artists {
+		record(ix.artists, "artists", a.ID, i)
+	}
+	for i, l := range d.Locations.Index {
+		record(ix.locations, "locations", l.ID, i)
+	}
+	for i, dt := range d.Dates.Index {
+		record(ix.dates, "dates", dt.ID, i)
+	}
+	for i, r := range d.Relations.Index {
+		record(ix.relations, "relation", r.ID, i)
+	}
+	return ix
+}
+
+func sortedKeys(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func sortedLocations(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,99 @@
+package validate
+
+import (
+	"context"
+	"testing"
+
+	"groupie-tracker-search-bar/internal/fetch"
+	"groupie-tracker-search-bar/internal/models"
+)
+
+// brokenDataset has artist 2 without a relation entry, a relation for a
+// location artist 1 never lists, a malformed date, and an orphan dates entry.
+func brokenDataset() models.Dataset {
+	return models.Dataset{
+		Artists: []models.Artist{
+			{ID: 1, Name: "Artist 1", FirstAlbum: "01-02-1990"},
+			{ID: 2, Name: "Artist 2", FirstAlbum: "03-04-1995"},
+		},
+		Locations: models.LocationsData{Index: []models.Location{
+			{ID: 2, Locations: []string{"paris-france"}},
+			{ID: 1, Locations: []string{"london-uk"}},
+		}},
+		Dates: models.DatesData{Index: []models.Date{
+			{ID: 1, Dates: []string{"*01-01-2020", "2020/01/02"}},
+			{ID: 2, Dates: []string{"05-05-2020"}},
+			{ID: 9, Dates: []string{"06-06-2020"}},
+		}},
+		Relations: models.RelationsData{Index: []models.Relation{
+			{ID: 1, DatesLocations: map[string][]string{
+				"london-uk":      {"01-01-2020", "2020/01/02"},
+				"berlin-germany": {"03-01-2020"},
+			}},
+		}},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	report := Check(brokenDataset())
+
+	counts := map[string]int{}
+	for _, issue := range report.Issues {
+		counts[issue.Kind]++
+	}
+	expected := map[string]int{
+		MissingEntry:    1, // artist 2 has no relation
+		OrphanEntry:     1, // dates entry 9
+		UnknownLocation: 1, // berlin-germany
+		MalformedDate:   2, // 2020/01/02 in dates and in relation
+	}
+	for kind, n := range expected {
+		if counts[kind] != n {
+			t.Errorf("expected %d %s issues, got %d (%s)", n, kind, counts[kind], report.Summary())
+		}
+	}
+}
+
+func TestCheckEmbeddedDataset(t *testing.T) {
+	dataset, err := fetch.FetchAllData(context.Background(), fetch.EmbeddedSource())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report := Check(dataset); !report.OK() {
+		t.Errorf("expected the embedded dataset to be consistent, got %s", report.Summary())
+	}
+}
+
+func TestApply(t *testing.T) {
+	if _, _, err := Apply(brokenDataset(), Strict); err == nil {
+		t.Errorf("expected the strict policy to refuse the dataset")
+	}
+
+	degraded, _, err := Apply(brokenDataset(), Degrade)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(degraded.Artists) != 1 || degraded.Artists[0].ID != 1 {
+		t.Fatalf("expected only artist 1 to survive, got %v", degraded.Artists)
+	}
+	if got := degraded.Locations.Index[0].ID; got != 1 {
+		t.Errorf("expected the locations to be aligned with artist 1, got ID %d", got)
+	}
+	if got := degraded.Dates.Index[0].Dates; len(got) != 1 {
+		t.Errorf("expected the malformed date to be dropped, got %v", got)
+	}
+	if _, ok := degraded.Relations.Index[0].DatesLocations["berlin-germany"]; ok {
+		t.Errorf("expected the unknown location to be dropped")
+	}
+	if report := Check(degraded); !report.OK() {
+		t.Errorf("expected the degraded dataset to be consistent, got %s", report.Summary())
+	}
+
+	warned, _, err := Apply(brokenDataset(), Warn)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(warned.Artists) != 2 || len(warned.Relations.Index) != 2 || warned.Relations.Index[1].ID != 2 {
+		t.Errorf("expected both artists with aligned entries, got %d artists and relations %v", len(warned.Artists), warned.Relations.Index)
+	}
+}
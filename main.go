@@ -1,21 +1,127 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 
 	"groupie-tracker-search-bar/internal/api"
+	"groupie-tracker-search-bar/internal/catalog"
+	"groupie-tracker-search-bar/internal/fetch"
+	"groupie-tracker-search-bar/internal/geocode"
+	"groupie-tracker-search-bar/internal/snapshot"
+	"groupie-tracker-search-bar/internal/validate"
 )
 
 func main() {
-	err := api.InitData()
+	sourceKind := flag.String("source", "http", "data source: http, dir or embedded")
+	sourceLocation := flag.String("source-location", "", "base URL for the http source or directory for the dir source")
+	strict := flag.Bool("strict", false, "reject upstream payloads with fields the models do not declare")
+	fetchTimeout := flag.Duration("fetch-timeout", fetch.DefaultTimeout, "deadline for each request to the http source")
+	exportPath := flag.String("export", "", "write a snapshot of the data source to this file and exit")
+	snapshotPath := flag.String("snapshot", "", "snapshot file to boot from when the data source is unreachable")
+	integrity := flag.String("integrity", "degrade", "integrity policy for loaded data: strict, degrade or warn")
+	refreshInterval := flag.Duration("refresh", 0, "reload the data from the data source at this interval (0 disables)")
+	geocoderProvider := flag.String("geocoder", "mapbox", "geocoder for the artist maps: mapbox, nominatim, static or gazetteer")
+	geocoderURL := flag.String("geocoder-url", "", "base URL of the mapbox or nominatim geocoder (defaults to the public service)")
+	geocoderToken := flag.String("geocoder-token", "", "access token for the mapbox geocoder (defaults to $MAPBOX_TOKEN)")
+	geocoderTable := flag.String("geocoder-table", "", "JSON file of coordinates by location slug for the static geocoder")
+	geocodeTimeout := flag.Duration("geocode-timeout", geocode.DefaultTimeout, "deadline for each request to the geocoder")
+	geocodeCachePath := flag.String("geocode-cache", "", "file to keep geocoded coordinates in across restarts (empty keeps them in memory only)")
+	geocodeTTL := flag.Duration("geocode-ttl", geocode.DefaultTTL, "how long cached coordinates are kept")
+	geocodeNegativeTTL := flag.Duration("geocode-negative-ttl", geocode.DefaultNegativeTTL, "how long a location the geocoder cannot find is remembered")
+	geocodeConcurrency := flag.Int("geocode-concurrency", 4, "most requests to the geocoder in flight at once (0 is unlimited)")
+	geocodeRate := flag.Float64("geocode-rate", 10, "most requests to the geocoder per second (0 is unlimited; public Nominatim allows 1)")
+	useGazetteer := flag.Bool("gazetteer", true, "look locations up in the built-in gazetteer before asking the geocoder")
+	metricsAddr := flag.String("metrics-addr", "", "address to serve the geocode cache metrics on, e.g. localhost:6060 (empty disables)")
+	warm := flag.Bool("geocode-warm", false, "geocode every location of the data source into the cache and exit")
+	flag.Parse()
+	if *geocoderToken == "" {
+		*geocoderToken = os.Getenv("MAPBOX_TOKEN")
+	}
+
+	policy, err := validate.ParsePolicy(*integrity)
+	if err != nil {
+		log.Fatalf("Error selecting integrity policy: %v", err)
+	}
+
+	source, err := fetch.NewDataSource(fetch.SourceConfig{
+		Kind:     *sourceKind,
+		Location: *sourceLocation,
+		Timeout:  *fetchTimeout,
+		Strict:   *strict,
+	})
+	if err != nil {
+		log.Fatalf("Error selecting data source: %v", err)
+	}
+
+	// Without a Mapbox token the built-in gazetteer still locates the known places
+	provider := *geocoderProvider
+	if (provider == "mapbox" || provider == "") && *geocoderToken == "" {
+		log.Printf("No Mapbox token (set MAPBOX_TOKEN or -geocoder-token): locating concerts with the built-in gazetteer only")
+		provider = "gazetteer"
+	}
+	api.SetMapboxToken(*geocoderToken)
+
+	geocoder, err := geocode.New(geocode.Config{
+		Provider: provider,
+		URL:      *geocoderURL,
+		Token:    *geocoderToken,
+		Table:    *geocoderTable,
+		Timeout:  *geocodeTimeout,
+	})
+	if err != nil {
+		log.Fatalf("Error selecting geocoder: %v", err)
+	}
+	geocoder = geocode.NewThrottle(geocoder, *geocodeConcurrency, *geocodeRate)
+	if *geocodeCachePath != "" {
+		geocoder, err = geocode.OpenFileCache(*geocodeCachePath, geocoder, *geocodeTTL, *geocodeNegativeTTL)
+		if err != nil {
+			log.Fatalf("Error opening geocode cache: %v", err)
+		}
+	} else {
+		geocoder = geocode.NewMemoryCache(geocoder)
+	}
+	cache := geocoder
+	geocoder = geocode.NewSingleFlight(geocoder)
+	if *useGazetteer && provider != "gazetteer" {
+		geocoder = geocode.Chain{geocode.Gazetteer(), geocoder}
+	}
+	api.SetGeocoder(geocoder)
+
+	if *exportPath != "" {
+		exportSnapshot(source, *exportPath)
+		return
+	}
+	if *warm {
+		// The cache is warmed directly: through the gazetteer, the places it
+		// covers would never reach the geocoder
+		warmGeocodeCache(source, cache)
+		return
+	}
+
+	log.Printf("Loading data from %v", source)
+	err = api.InitData(context.Background(), source, policy)
+	if err != nil && *snapshotPath != "" {
+		log.Printf("Error initializing data: %v; falling back to snapshot %s", err, *snapshotPath)
+		err = initFromSnapshot(*snapshotPath, policy)
+	}
 	if err != nil {
 		log.Fatalf("Error initializing data: %v", err)
 	}
+	if *refreshInterval > 0 {
+		api.StartRefresher(context.Background(), source, policy, *refreshInterval)
+	}
+
+	// The site gets its own mux: packages such as expvar register debug
+	// handlers on http.DefaultServeMux, which must not be public
+	mux := http.NewServeMux()
 
 	// Handle the root path "/"
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/" {
 			api.IndexHandler(w, r)
 		} else {
@@ -23,14 +129,74 @@ func main() {
 		}
 	})
 
-	http.HandleFunc("/artists", api.ArtistsHandler)
-	http.HandleFunc("/artist/", api.ArtistDetailHandler)
-	http.HandleFunc("/search", api.SearchHandler)
+	mux.HandleFunc("/artists", api.ArtistsHandler)
+	mux.HandleFunc("/artist/", api.ArtistDetailHandler)
+	mux.HandleFunc("/search", api.SearchHandler)
+	mux.HandleFunc("/results", api.ResultsHandler)
 
 	// Serve static files
 	fs := http.FileServer(http.Dir("./static"))
-	http.Handle("/static/", http.StripPrefix("/static/", fs))
+	mux.Handle("/static/", http.StripPrefix("/static/", fs))
+
+	if *metricsAddr != "" {
+		go serveMetrics(*metricsAddr)
+	}
 
 	fmt.Println("Server is running on port localhost:8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	log.Fatal(http.ListenAndServe(":8080", mux))
+}
+
+// serveMetrics publishes the geocode cache counters at /debug/vars on a
+// listener of its own, leaving out the other expvar variables such as the
+// command line, which may hold an access token
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/vars", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprintf(w, "{%q: %s}\n", "geocode_cache", geocode.Metrics)
+	})
+	log.Printf("Serving metrics on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
+
+// exportSnapshot fetches the full dataset from source and saves it as a snapshot file
+func exportSnapshot(source fetch.DataSource, path string) {
+	dataset, err := fetch.FetchAllData(context.Background(), source)
+	if err != nil {
+		log.Fatalf("Error fetching data for snapshot: %v", err)
+	}
+	err = snapshot.Write(path, snapshot.New(dataset, fmt.Sprint(source)))
+	if err != nil {
+		log.Fatalf("Error writing snapshot: %v", err)
+	}
+	log.Printf("Wrote snapshot of %d artists to %s", len(dataset.Artists), path)
+}
+
+// initFromSnapshot loads the application data from a previously exported snapshot
+func initFromSnapshot(path string, policy validate.Policy) error {
+	snap, err := snapshot.Read(path)
+	if err != nil {
+		return err
+	}
+	log.Printf("Loading data from %v", snap)
+	return api.InitData(context.Background(), snap, policy)
+}
+
+// warmGeocodeCache geocodes every location in source, filling the geocoder's cache
+func warmGeocodeCache(source fetch.DataSource, geocoder geocode.Geocoder) {
+	dataset, err := fetch.FetchAllData(context.Background(), source)
+	if err != nil {
+		log.Fatalf("Error fetching data to warm the geocode cache: %v", err)
+	}
+	places := catalog.New(dataset).Places()
+	failed, err := geocode.Warm(context.Background(), geocoder, places)
+	for _, place := range places {
+		if err, ok := failed[place]; ok {
+			log.Printf("Could not geocode: %v", err)
+		}
+	}
+	if err != nil {
+		log.Fatalf("Error saving the geocode cache: %v", err)
+	}
+	log.Printf("Geocoded %d of %d locations with %v", len(places)-len(failed), len(places), geocoder)
 }
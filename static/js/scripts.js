@@ -5,6 +5,9 @@ document.addEventListener("DOMContentLoaded", function() {
     const resultsContainer = document.getElementById('search-results');
     let currentIndex = -1; // Track the currently highlighted index
 
+    const dropdownLimit = 8; // Results shown in the dropdown
+    const dropdownPerCategory = 4; // Results shown per category, so one category cannot fill the list
+
     // Function to search for artists based on user query
     function searchArtists(query) {
         console.log("Search query:", query);
@@ -14,31 +17,96 @@ document.addEventListener("DOMContentLoaded", function() {
             return;
         }
 
-        fetch(`/search?q=${encodeURIComponent(query)}`)
+        fetch(`/search?q=${encodeURIComponent(query)}&limit=${dropdownLimit}&per_category=${dropdownPerCategory}`)
             .then(response => response.json())
             .then(data => {
                 resultsContainer.innerHTML = '';
                 currentIndex = -1; // Reset the index when new search results are loaded
 
+                // A malformed query (e.g. "genre:rock") comes back as {error: "..."}
+                if (data.error) {
+                    const errorItem = document.createElement('div');
+                    errorItem.className = 'search-error';
+                    errorItem.textContent = data.error;
+                    resultsContainer.appendChild(errorItem);
+                    resultsContainer.style.display = 'block';
+                    return;
+                }
+
                 // Display each search result
-                data.forEach((result, index) => {
+                data.results.forEach((result, index) => {
                     const resultItem = document.createElement('div');
                     resultItem.className = 'search-result-item';
-                    resultItem.textContent = result.name;
+                    resultItem.appendChild(renderSearchResult(result));
 
-                    // Direct the user to the artist detail page on click
+                    // Direct the user to the part of the artist page the result came from
                     resultItem.onclick = function() {
-                        window.location.href = `/artist/${result.id}`;
+                        window.location.href = result.url;
                     };
 
                     resultsContainer.appendChild(resultItem);
                 });
 
+                // Tell how many results the dropdown leaves out and link to all of them
+                if (data.total > data.results.length) {
+                    const moreItem = document.createElement('a');
+                    moreItem.className = 'search-more';
+                    moreItem.href = `/results?q=${encodeURIComponent(query)}`;
+                    moreItem.textContent = `See all ${data.total} results`;
+                    resultsContainer.appendChild(moreItem);
+                }
+
                 resultsContainer.style.display = 'block'; // Show search results
             })
             .catch(error => console.error('Error fetching search results:', error));
     }
 
+    const searchCategories = {
+        artist: 'Artist',
+        member: 'Member',
+        first_album: 'First album',
+        creation_date: 'Created',
+        location: 'Location',
+        concert_date: 'Concert'
+    };
+
+    // Build the content of a search result: its category, the matched text with
+    // the matching parts highlighted, and the artist it belongs to
+    function renderSearchResult(result) {
+        const fragment = document.createDocumentFragment();
+
+        const category = document.createElement('span');
+        category.className = 'search-result-category';
+        category.textContent = searchCategories[result.category] || result.category;
+        fragment.appendChild(category);
+
+        // Highlights count characters (code points), not UTF-16 units
+        const chars = Array.from(result.text);
+        let last = 0;
+        result.highlights.forEach(span => {
+            fragment.appendChild(document.createTextNode(chars.slice(last, span.start).join('')));
+            const mark = document.createElement('mark');
+            mark.textContent = chars.slice(span.start, span.end).join('');
+            fragment.appendChild(mark);
+            last = span.end;
+        });
+        fragment.appendChild(document.createTextNode(chars.slice(last).join('')));
+
+        if (result.category !== 'artist') {
+            const artist = document.createElement('span');
+            artist.className = 'search-result-artist';
+            artist.textContent = result.artist_name;
+            if (result.concerts && result.concerts.length > 0) {
+                artist.textContent += ` in ${result.concerts[0].place}`;
+                if (result.concerts.length > 1) {
+                    artist.textContent += ` and ${result.concerts.length - 1} more`;
+                }
+            }
+            fragment.appendChild(artist);
+        }
+        return fragment;
+    }
+
     // Handle input events in the search bar
     searchBar.addEventListener('input', function() {
         const query = searchBar.value;
@@ -199,37 +267,6 @@ document.addEventListener("DOMContentLoaded", function() {
         }
     }
 
-    // Initialize map for artist details page
-    const mapContainer = document.getElementById('map');
-    if (mapContainer) {
-        initializeMapbox();
-    }
-
-    function initializeMapbox() {
-        mapboxgl.accessToken = 'your-mapbox-access-token';
-
-        const concertLocations = JSON.parse(document.getElementById('ConcertLocationsJSON').innerText);
-
-        const map = new mapboxgl.Map({
-            container: 'map',
-            style: 'mapbox://styles/mapbox/streets-v11',
-            center: [0, 0],
-            zoom: 2 // Default zoom level
-        });
-
-        concertLocations.forEach(function(location) {
-            const popup = new mapboxgl.Popup({ offset: 25 }).setText(location.locationName);
-
-            new mapboxgl.Marker()
-                .setLngLat(location.coordinates)
-                .setPopup(popup)
-                .addTo(map);
-        });
-
-        // Ensure that the map resizes correctly
-        map.resize();
-    }
-
     // // Toggle functionality for search bar and navigation links
     // const hamburger = document.querySelector(".hamburger");
     // const navLinks = document.querySelector(".nav-links");
@@ -271,7 +308,13 @@ document.addEventListener("DOMContentLoaded", function() {
 });
 
 function initializeMapbox() {
-    mapboxgl.accessToken = 'pk.eyJ1IjoiYXRob29oIiwiYSI6ImNtMWY2N3prZjJsN3MybHNjMWd3bThzOXcifQ.HNgAHQBkzGdrnuS1MtwYlQ';
+    // The server hands over its Mapbox token; without one there is no map to draw
+    const token = document.getElementById('map').dataset.mapboxToken;
+    if (!token) {
+        document.getElementById('map').textContent = 'The map is unavailable: no Mapbox access token is configured.';
+        return;
+    }
+    mapboxgl.accessToken = token;
 
     const concertLocations = JSON.parse(document.getElementById('ConcertLocationsJSON').innerText);
 
@@ -283,6 +326,10 @@ function initializeMapbox() {
     });
 
     concertLocations.forEach(function(location) {
+        // Locations the server could not geocode have no coordinates
+        if (location.unresolved || !location.coordinates) {
+            return;
+        }
         const popup = new mapboxgl.Popup({ offset: 25 }).setText(location.locationName);
 
         new mapboxgl.Marker()
@@ -295,6 +342,18 @@ function initializeMapbox() {
     map.resize();
 }
 
+// Show concert dates in the visitor's locale, keeping the server text as a fallback
+document.addEventListener("DOMContentLoaded", function() {
+    document.querySelectorAll('.tour-dates time[datetime]').forEach(function(element) {
+        const date = new Date(element.getAttribute('datetime') + 'T00:00:00Z');
+        if (!isNaN(date)) {
+            element.textContent = date.toLocaleDateString(undefined, {
+                weekday: 'short', day: 'numeric', month: 'short', year: 'numeric', timeZone: 'UTC'
+            });
+        }
+    });
+});
+
 // Optional: Additional configuration if you want to control the slider behavior.
 document.addEventListener('DOMContentLoaded', function() {
     const slideTrack = document.querySelector('.slide-track');
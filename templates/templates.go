@@ -0,0 +1,8 @@
+// Package templates holds the page templates, compiled into the binary so the
+// server renders them whatever its working directory.
+package templates
+
+import "embed"
+
+//go:embed *.html
+var FS embed.FS